@@ -6,14 +6,20 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"time"
 
 	"helpdesk/internal/config"
 	"helpdesk/internal/database"
+	"helpdesk/internal/features/admin"
 	"helpdesk/internal/features/category"
 	"helpdesk/internal/features/division"
+	"helpdesk/internal/features/search"
 	"helpdesk/internal/features/user"
 	"helpdesk/internal/middleware"
+	"helpdesk/internal/utils/query"
+	"helpdesk/internal/utils/response"
 	"helpdesk/internal/utils/uploads"
+	"helpdesk/internal/utils/validator"
 
 	"github.com/labstack/echo/v5"
 )
@@ -25,49 +31,114 @@ func main() {
 		Level: slog.LevelInfo,
 	}))
 
-	db := database.NewPostgres(cfg.DBConnString())
+	db := database.Connect(cfg.DBConnString(), cfg.DBReplicaConnString(), cfg.DBMaxOpenConns, cfg.DBMaxIdleConns)
 	defer db.Close()
 
 	logger.Info("connected to database", "host", cfg.DBHost, "database", cfg.DBName)
+	if db.HasReplica() {
+		logger.Info("connected to read replica", "host", cfg.DBReplicaHost)
+	}
 
 	if err := uploads.EnsureUploadDirs(); err != nil {
 		log.Fatalf("failed to create upload directories: %v", err)
 	}
 	logger.Info("upload directories ready")
 
+	location, err := time.LoadLocation(cfg.AppTimezone)
+	if err != nil {
+		log.Fatalf("invalid APP_TIMEZONE %q: %v", cfg.AppTimezone, err)
+	}
+
+	listOptions := response.ListOptions{
+		AllowUnpaginated: cfg.PaginationAllowAll,
+		MaxPage:          cfg.PaginationMaxPage,
+		Location:         location,
+	}
+	queryTimeouts := query.Timeouts(cfg.QueryTimeouts)
+
+	var disposableDomains validator.DisposableDomains
+	if cfg.RejectDisposableEmails {
+		disposableDomains = validator.NewDisposableDomains(cfg.DisposableEmailDomains)
+	}
+
 	e := echo.New()
 
-	e.Use(middleware.RequestID)
+	e.Use(middleware.RequestID(cfg.RequestIDValidation))
+	e.Use(middleware.Warnings())
+	e.Use(middleware.FeatureFlags(cfg.ExperimentalFeatureFlags))
+	e.Use(middleware.PaginationStyle(cfg.PaginationStyle))
 	e.Use(middleware.Recovery(logger))
-	e.Use(middleware.Logger(logger))
+	e.Use(middleware.Logger(logger, middleware.LoggerConfig{
+		LatencyBudgets: cfg.LatencyBudgets,
+		SampleRate:     cfg.LogSampleRate,
+	}))
 	e.Use(middleware.CORS())
+	e.Use(middleware.Deprecations(cfg.DeprecatedRoutes))
+	limiter := middleware.NewConcurrencyLimiter(cfg.MaxConcurrentRequests, cfg.ConcurrencyQueueTimeout, "/api/v1/health")
+	e.Use(limiter.Middleware())
+	e.Use(middleware.RequestTimeout(cfg.RequestTimeout))
+	readOnly := middleware.NewReadOnlyMode(cfg.ReadOnlyMode, "/api/v1/health", "/api/v1/admin/read-only")
+	e.Use(readOnly.Middleware())
+	if cfg.Features.SecureHeaders {
+		e.Use(middleware.SecureHeaders(cfg.HSTSMaxAge))
+	}
 
-	categoryRepo := category.NewRepository(db)
-	categoryService := category.NewService(categoryRepo, logger)
+	categoryRepo := category.NewRepository(db.Primary, db.Read, queryTimeouts)
+	categoryService := category.NewService(categoryRepo, logger, cfg.ListCacheTTL, listOptions, cfg.DeleteMode)
 	categoryHandler := category.NewHandler(categoryService)
 
-	divisionRepo := division.NewRepository(db)
-	divisionService := division.NewService(divisionRepo, logger)
+	divisionRepo := division.NewRepository(db.Primary, db.Read, queryTimeouts)
+	divisionService := division.NewService(divisionRepo, logger, cfg.RequireActiveDivision, cfg.ListCacheTTL, listOptions, cfg.DeleteMode)
 	divisionHandler := division.NewHandler(divisionService)
 
-	userRepo := user.NewRepository(db)
-	userService := user.NewService(userRepo, divisionService, logger, cfg.BaseURL)
-	userHandler := user.NewHandler(userService)
+	userRepo := user.NewRepository(db.Primary, db.Read, queryTimeouts)
+	userService := user.NewService(userRepo, divisionService, logger, cfg.BaseURL, user.UploadLimits{
+		Default: cfg.MaxUploadsPerUser,
+		Admin:   cfg.MaxUploadsPerAdmin,
+	}, user.CreateDefaults{
+		Role:       cfg.DefaultUserRole,
+		DivisionID: cfg.DefaultUserDivisionID,
+	}, user.CredentialPolicy{
+		RejectCommonPasswords:   cfg.PasswordCheckCommon,
+		DisposableEmailDomains:  disposableDomains,
+		GeneratedPasswordLength: cfg.GeneratedPasswordLength,
+	}, listOptions)
+	userHandler := user.NewHandler(userService, uploads.ImageLimits{
+		MaxWidth:  cfg.MaxImageWidth,
+		MaxHeight: cfg.MaxImageHeight,
+	})
+
+	searchService := search.NewService(userService, categoryService, divisionService, logger, queryTimeouts)
+	searchHandler := search.NewHandler(searchService)
+
+	adminRepo := admin.NewRepository(db.Primary)
+	adminService := admin.NewService(adminRepo, userService, categoryService, divisionService, readOnly, cfg, logger)
+	adminHandler := admin.NewHandler(adminService)
+
+	adminOnly := middleware.AdminKey(cfg.AdminAPIKey)
+	hardDeleteOnly := middleware.When(response.HardDeleteRequested(cfg.DeleteMode), adminOnly)
 
 	e.Static("/uploads", "uploads")
 
-	api := e.Group("/api/v1")
+	api := e.Group("/api/v1", middleware.When(response.UnpaginatedRequested, adminOnly))
 
 	api.GET("/health", func(c *echo.Context) error {
 		return c.JSON(http.StatusOK, map[string]interface{}{
-			"status": "ok",
-			"app":    cfg.AppName,
+			"status":           "ok",
+			"app":              cfg.AppName,
+			"inFlightRequests": limiter.InFlight(),
 		})
 	})
 
-	category.RegisterRoutes(api, categoryHandler)
-	division.RegisterRoutes(api, divisionHandler)
-	user.RegisterRoutes(api, userHandler)
+	api.GET("/features", func(c *echo.Context) error {
+		return response.OK(c, "Features retrieved successfully", cfg.Features)
+	})
+
+	category.RegisterRoutes(api, categoryHandler, adminOnly, hardDeleteOnly)
+	division.RegisterRoutes(api, divisionHandler, adminOnly, hardDeleteOnly)
+	user.RegisterRoutes(api, userHandler, adminOnly)
+	admin.RegisterRoutes(api, adminHandler, adminOnly, cfg.SeedDemoEnabled)
+	search.RegisterRoutes(api, searchHandler)
 	addr := ":" + cfg.AppPort
 	logger.Info("starting server", "address", addr, "app", cfg.AppName)
 	fmt.Printf("🚀 Server started on %s\n", addr)
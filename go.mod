@@ -8,6 +8,7 @@ require (
 	github.com/labstack/echo/v5 v5.0.4
 	github.com/lib/pq v1.11.2
 	golang.org/x/crypto v0.48.0
+	golang.org/x/image v0.36.0
 )
 
 require golang.org/x/time v0.14.0 // indirect
@@ -3,19 +3,79 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
+type Features struct {
+	SecureHeaders bool `json:"secureHeaders"`
+}
+
 type Config struct {
-	AppName string
-	AppPort string
-	BaseURL string
+	AppName     string
+	AppPort     string
+	BaseURL     string
+	AppTimezone string
+
+	Features Features
+
+	ExperimentalFeatureFlags []string
+
+	ReadOnlyMode bool
+
+	HSTSMaxAge int
+
+	PaginationAllowAll bool
+	PaginationStyle    string
+	PaginationMaxPage  int
+
+	PasswordCheckCommon     bool
+	GeneratedPasswordLength int
+
+	RejectDisposableEmails bool
+	DisposableEmailDomains []string
+
+	RequireActiveDivision bool
+	DeleteMode            string
+
+	DefaultUserRole       string
+	DefaultUserDivisionID int
+
+	ListCacheTTL time.Duration
+
+	SeedDemoEnabled bool
+
+	MaxImageWidth  int
+	MaxImageHeight int
+
+	MaxUploadsPerUser  int
+	MaxUploadsPerAdmin int
+
+	LatencyBudgets   map[string]time.Duration
+	DeprecatedRoutes map[string]time.Time
+	QueryTimeouts    map[string]time.Duration
+	RequestTimeout   time.Duration
+	LogSampleRate    int
+
+	MaxConcurrentRequests   int
+	ConcurrencyQueueTimeout time.Duration
+
+	RequestIDValidation string
+
+	AdminAPIKey string
 
-	DBHost     string
-	DBPort     string
-	DBUser     string
-	DBPassword string
-	DBName     string
-	DBSSLMode  string
+	DBHost         string
+	DBPort         string
+	DBUser         string
+	DBPassword     string
+	DBName         string
+	DBSSLMode      string
+	DBMaxOpenConns int
+	DBMaxIdleConns int
+
+	DBReplicaHost string
+	DBReplicaPort string
 }
 
 func Load() *Config {
@@ -24,20 +84,90 @@ func Load() *Config {
 		AppPort: getEnv("APP_PORT", "8080"),
 		BaseURL: getEnv("BASE_URL", "http://localhost:8080"),
 
+		AppTimezone: getEnv("APP_TIMEZONE", "UTC"),
+
+		Features: Features{
+			SecureHeaders: getEnvBool("FEATURE_SECURE_HEADERS", true),
+		},
+
+		ExperimentalFeatureFlags: getEnvList("EXPERIMENTAL_FEATURE_FLAGS"),
+
+		ReadOnlyMode: getEnvBool("READ_ONLY_MODE", false),
+
+		HSTSMaxAge: getEnvInt("HSTS_MAX_AGE", 0),
+
+		PaginationAllowAll: getEnvBool("PAGINATION_ALLOW_ALL", false),
+		PaginationStyle:    getEnv("PAGINATION_STYLE", "body"),
+		PaginationMaxPage:  getEnvInt("PAGINATION_MAX_PAGE", 1000),
+
+		PasswordCheckCommon:     getEnvBool("PASSWORD_CHECK_COMMON", true),
+		GeneratedPasswordLength: getEnvInt("GENERATED_PASSWORD_LENGTH", 16),
+
+		RejectDisposableEmails: getEnvBool("REJECT_DISPOSABLE_EMAILS", false),
+		DisposableEmailDomains: getEnvList("DISPOSABLE_EMAIL_DOMAINS"),
+
+		RequireActiveDivision: getEnvBool("REQUIRE_ACTIVE_DIVISION", true),
+		DeleteMode:            getEnv("DELETE_MODE", "soft"),
+
+		DefaultUserRole:       getEnv("DEFAULT_USER_ROLE", ""),
+		DefaultUserDivisionID: getEnvInt("DEFAULT_USER_DIVISION_ID", 0),
+
+		ListCacheTTL: getEnvDuration("LIST_CACHE_TTL", 0),
+
+		SeedDemoEnabled: getEnvBool("SEED_DEMO_ENABLED", false),
+
+		MaxImageWidth:  getEnvInt("MAX_IMAGE_WIDTH", 4096),
+		MaxImageHeight: getEnvInt("MAX_IMAGE_HEIGHT", 4096),
+
+		MaxUploadsPerUser:  getEnvInt("MAX_UPLOADS_PER_USER", 0),
+		MaxUploadsPerAdmin: getEnvInt("MAX_UPLOADS_PER_ADMIN", 0),
+
+		LatencyBudgets:   parseDurationMap(os.Getenv("LATENCY_BUDGETS")),
+		DeprecatedRoutes: parseDeprecatedRoutes(os.Getenv("DEPRECATED_ROUTES")),
+		QueryTimeouts:    parseDurationMap(os.Getenv("QUERY_TIMEOUTS")),
+		RequestTimeout:   getEnvDuration("REQUEST_TIMEOUT", 30*time.Second),
+		LogSampleRate:    getEnvInt("LOG_SAMPLE_RATE", 1),
+
+		MaxConcurrentRequests:   getEnvInt("MAX_CONCURRENT_REQUESTS", 0),
+		ConcurrencyQueueTimeout: getEnvDuration("CONCURRENCY_QUEUE_TIMEOUT", 0),
+
+		RequestIDValidation: getEnv("REQUEST_ID_VALIDATION", "lenient"),
+
+		AdminAPIKey: getEnv("ADMIN_API_KEY", ""),
+
 		DBHost:     getEnv("DB_HOST", "localhost"),
 		DBPort:     getEnv("DB_PORT", "5432"),
 		DBUser:     getEnv("DB_USER", "postgres"),
 		DBPassword: getEnv("DB_PASSWORD", "postgres"),
 		DBName:     getEnv("DB_NAME", "helpdesk"),
 		DBSSLMode:  getEnv("DB_SSLMODE", "disable"),
+
+		DBMaxOpenConns: getEnvInt("DB_MAX_OPEN_CONNS", 25),
+		DBMaxIdleConns: getEnvInt("DB_MAX_IDLE_CONNS", 25),
+
+		DBReplicaHost: getEnv("DB_REPLICA_HOST", ""),
+		DBReplicaPort: getEnv("DB_REPLICA_PORT", getEnv("DB_PORT", "5432")),
 	}
 }
 
 func (c *Config) DBConnString() string {
+	return c.connString(c.DBHost, c.DBPort)
+}
+
+// DBReplicaConnString is empty when no read replica is configured. The
+// replica uses the primary's credentials and database name.
+func (c *Config) DBReplicaConnString() string {
+	if c.DBReplicaHost == "" {
+		return ""
+	}
+	return c.connString(c.DBReplicaHost, c.DBReplicaPort)
+}
+
+func (c *Config) connString(host, port string) string {
 	return fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		c.DBHost,
-		c.DBPort,
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s timezone=UTC",
+		host,
+		port,
 		c.DBUser,
 		c.DBPassword,
 		c.DBName,
@@ -52,3 +182,81 @@ func getEnv(key, fallback string) string {
 	}
 	return env
 }
+
+func getEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func getEnvList(key string) []string {
+	values := []string{}
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// parseDurationMap reads "key=duration" pairs separated by commas, e.g.
+// "GET /api/v1/users=200ms,PATCH /api/v1/users/:id=500ms" or "list=2s".
+func parseDurationMap(value string) map[string]time.Duration {
+	budgets := make(map[string]time.Duration)
+
+	for _, entry := range strings.Split(value, ",") {
+		route, rawBudget, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			continue
+		}
+
+		budget, err := time.ParseDuration(strings.TrimSpace(rawBudget))
+		if err != nil || budget <= 0 {
+			continue
+		}
+
+		budgets[strings.Join(strings.Fields(route), " ")] = budget
+	}
+
+	return budgets
+}
+
+// parseDeprecatedRoutes reads "METHOD /route=YYYY-MM-DD" pairs separated by
+// commas, where the date is the planned removal (sunset) day.
+func parseDeprecatedRoutes(value string) map[string]time.Time {
+	routes := make(map[string]time.Time)
+
+	for _, entry := range strings.Split(value, ",") {
+		route, rawSunset, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			continue
+		}
+
+		sunset, err := time.Parse("2006-01-02", strings.TrimSpace(rawSunset))
+		if err != nil {
+			continue
+		}
+
+		routes[strings.Join(strings.Fields(route), " ")] = sunset
+	}
+
+	return routes
+}
@@ -7,14 +7,49 @@ import (
 	_ "github.com/lib/pq"
 )
 
-func NewPostgres(conn string) *sqlx.DB {
+// DB holds the primary connection and the handle for replica-tolerant reads.
+// Read is the primary itself when no replica is configured.
+type DB struct {
+	Primary *sqlx.DB
+	Read    *sqlx.DB
+}
+
+func NewPostgres(conn string, maxOpenConns, maxIdleConns int) *sqlx.DB {
 	db, err := sqlx.Connect("postgres", conn)
 	if err != nil {
 		log.Fatal("Db connection error: ", err)
 	}
 
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(25)
+	db.SetMaxOpenConns(maxOpenConns)
+	db.SetMaxIdleConns(maxIdleConns)
 
 	return db
 }
+
+// Connect opens the primary and, when replicaConn is set, a read replica.
+func Connect(primaryConn, replicaConn string, maxOpenConns, maxIdleConns int) *DB {
+	primary := NewPostgres(primaryConn, maxOpenConns, maxIdleConns)
+	if replicaConn == "" {
+		return &DB{Primary: primary, Read: primary}
+	}
+
+	return &DB{
+		Primary: primary,
+		Read:    NewPostgres(replicaConn, maxOpenConns, maxIdleConns),
+	}
+}
+
+func (db *DB) HasReplica() bool {
+	return db.Read != db.Primary
+}
+
+func (db *DB) Close() error {
+	var replicaErr error
+	if db.HasReplica() {
+		replicaErr = db.Read.Close()
+	}
+	if err := db.Primary.Close(); err != nil {
+		return err
+	}
+	return replicaErr
+}
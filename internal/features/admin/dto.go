@@ -0,0 +1,109 @@
+package admin
+
+import (
+	"time"
+
+	"helpdesk/internal/config"
+	"helpdesk/internal/utils/cache"
+	"helpdesk/internal/utils/validator"
+)
+
+const (
+	DefaultRecentLimit = 5
+	MaxRecentLimit     = 20
+)
+
+type GetRecentQuery struct {
+	Limit int `query:"limit"`
+}
+
+type RecentItem struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type RecentResponse struct {
+	Users      []RecentItem `json:"users"`
+	Categories []RecentItem `json:"categories"`
+	Divisions  []RecentItem `json:"divisions"`
+}
+
+type UpdateReadOnlyRequest struct {
+	Enabled *bool `json:"enabled"`
+}
+
+type ReadOnlyResponse struct {
+	Enabled bool `json:"enabled"`
+}
+
+type CacheStatsResponse struct {
+	Categories cache.Stats `json:"categories"`
+	Divisions  cache.Stats `json:"divisions"`
+}
+
+// ConfigResponse is the allowlist of settings GET /admin/config reveals. A
+// new config field stays private until it is copied here.
+type ConfigResponse struct {
+	AppName                  string          `json:"appName"`
+	AppPort                  string          `json:"appPort"`
+	Features                 config.Features `json:"features"`
+	ExperimentalFeatureFlags []string        `json:"experimentalFeatureFlags"`
+	DBMaxOpenConns           int             `json:"dbMaxOpenConns"`
+	DBMaxIdleConns           int             `json:"dbMaxIdleConns"`
+	DBReplicaEnabled         bool            `json:"dbReplicaEnabled"`
+	MaxUploadsPerUser        int             `json:"maxUploadsPerUser"`
+	MaxUploadsPerAdmin       int             `json:"maxUploadsPerAdmin"`
+	MaxImageWidth            int             `json:"maxImageWidth"`
+	MaxImageHeight           int             `json:"maxImageHeight"`
+}
+
+func (q *GetRecentQuery) Normalize() (int, error) {
+	if q.Limit == 0 {
+		return DefaultRecentLimit, nil
+	}
+
+	v := validator.New()
+	v.Check(q.Limit > 0 && q.Limit <= MaxRecentLimit, "limit", "limit must be between 1 and 20")
+	if !v.Valid() {
+		return 0, v.ToAppError()
+	}
+
+	return q.Limit, nil
+}
+
+func (r *UpdateReadOnlyRequest) Validate() error {
+	v := validator.New()
+
+	v.Check(r.Enabled != nil, "enabled", "enabled is required")
+
+	if !v.Valid() {
+		return v.ToAppError()
+	}
+
+	return nil
+}
+
+func toConfigResponse(cfg *config.Config) *ConfigResponse {
+	return &ConfigResponse{
+		AppName:                  cfg.AppName,
+		AppPort:                  cfg.AppPort,
+		Features:                 cfg.Features,
+		ExperimentalFeatureFlags: cfg.ExperimentalFeatureFlags,
+		DBMaxOpenConns:           cfg.DBMaxOpenConns,
+		DBMaxIdleConns:           cfg.DBMaxIdleConns,
+		DBReplicaEnabled:         cfg.DBReplicaHost != "",
+		MaxUploadsPerUser:        cfg.MaxUploadsPerUser,
+		MaxUploadsPerAdmin:       cfg.MaxUploadsPerAdmin,
+		MaxImageWidth:            cfg.MaxImageWidth,
+		MaxImageHeight:           cfg.MaxImageHeight,
+	}
+}
+
+func toRecentItems[T any](items []T, mapper func(*T) RecentItem) []RecentItem {
+	results := make([]RecentItem, len(items))
+	for i := range items {
+		results[i] = mapper(&items[i])
+	}
+	return results
+}
@@ -0,0 +1,57 @@
+package admin
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"helpdesk/internal/config"
+)
+
+func TestConfigResponseOnlyExposesAllowlistedFields(t *testing.T) {
+	cfg := &config.Config{
+		AppName:       "Helpdesk API",
+		AppPort:       "8080",
+		BaseURL:       "https://helpdesk.internal.example",
+		AdminAPIKey:   "operator-key-value",
+		DBHost:        "db-primary.internal",
+		DBPort:        "6543",
+		DBUser:        "helpdesk-user",
+		DBPassword:    "db-password-value",
+		DBName:        "helpdesk-db-name",
+		DBReplicaHost: "db-replica.internal",
+		DBReplicaPort: "6544",
+	}
+
+	data, err := json.Marshal(toConfigResponse(cfg))
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	body := string(data)
+
+	for _, secret := range []string{"helpdesk.internal.example", "operator-key-value", "db-primary.internal", "6543", "helpdesk-user", "db-password-value", "helpdesk-db-name", "db-replica.internal", "6544"} {
+		if strings.Contains(body, secret) {
+			t.Errorf("config response contains %q: %s", secret, body)
+		}
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	var keys []string
+	for key := range decoded {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	want := []string{"appName", "appPort", "dbMaxIdleConns", "dbMaxOpenConns", "dbReplicaEnabled", "experimentalFeatureFlags", "features", "maxImageHeight", "maxImageWidth", "maxUploadsPerAdmin", "maxUploadsPerUser"}
+	if !slices.Equal(keys, want) {
+		t.Errorf("config response keys = %v, want %v", keys, want)
+	}
+	if decoded["dbReplicaEnabled"] != true {
+		t.Errorf("dbReplicaEnabled = %v, want true", decoded["dbReplicaEnabled"])
+	}
+}
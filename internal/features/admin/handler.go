@@ -0,0 +1,80 @@
+package admin
+
+import (
+	"helpdesk/internal/utils/errors"
+	"helpdesk/internal/utils/response"
+
+	"github.com/labstack/echo/v5"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+func (h *Handler) GetRecent(c *echo.Context) error {
+	var req GetRecentQuery
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Invalid query parameters"))
+	}
+
+	recent, err := h.service.GetRecent(c.Request().Context(), &req)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.OK(c, "Recent entities retrieved successfully", recent)
+}
+
+func (h *Handler) GetCacheStats(c *echo.Context) error {
+	return response.OK(c, "Cache stats retrieved successfully", h.service.GetCacheStats())
+}
+
+func (h *Handler) GetConfig(c *echo.Context) error {
+	return response.OK(c, "Configuration retrieved successfully", h.service.GetConfig())
+}
+
+func (h *Handler) GetReadOnly(c *echo.Context) error {
+	return response.OK(c, "Read-only mode retrieved successfully", h.service.GetReadOnly())
+}
+
+func (h *Handler) SeedDemo(c *echo.Context) error {
+	result, err := h.service.SeedDemo(c.Request().Context())
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.OK(c, "Demo data seeded successfully", result)
+}
+
+func (h *Handler) UpdateReadOnly(c *echo.Context) error {
+	var req UpdateReadOnlyRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, err)
+	}
+
+	result, err := h.service.UpdateReadOnly(&req)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.OK(c, "Read-only mode updated successfully", result)
+}
+
+func (h *Handler) DeleteDemo(c *echo.Context) error {
+	result, err := h.service.DeleteDemo(c.Request().Context())
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.OK(c, "Demo data deleted successfully", result)
+}
+
+func (h *Handler) ClearCaches(c *echo.Context) error {
+	return response.OK(c, "Caches cleared successfully", h.service.ClearCaches())
+}
@@ -0,0 +1,49 @@
+package admin
+
+import "helpdesk/internal/features/user"
+
+const DemoEmailDomain = "demo.helpdesk.local"
+
+type DemoUser struct {
+	Username    string
+	DisplayName string
+	Role        string
+	Division    string
+}
+
+type DemoTicket struct {
+	Title       string
+	Description string
+	Category    string
+	Priority    string
+	Status      string
+	CreatedBy   string
+	AssignedTo  string
+}
+
+var demoDivisions = []string{"Demo IT Support", "Demo Finance"}
+
+var demoCategories = []string{"Demo Hardware", "Demo Software", "Demo Network"}
+
+var demoUsers = []DemoUser{
+	{Username: "demo.admin", DisplayName: "Demo Admin", Role: user.RoleAdmin, Division: "Demo IT Support"},
+	{Username: "demo.it", DisplayName: "Demo IT", Role: user.RoleIT, Division: "Demo IT Support"},
+	{Username: "demo.staff", DisplayName: "Demo Staff", Role: user.RoleStaff, Division: "Demo Finance"},
+}
+
+var demoTickets = []DemoTicket{
+	{Title: "[Demo] Laptop will not boot", Description: "Demo ticket: laptop shows a black screen on start.", Category: "Demo Hardware", Priority: "URGENT", Status: "OPEN", CreatedBy: "demo.staff"},
+	{Title: "[Demo] Install spreadsheet add-in", Description: "Demo ticket: finance add-in needs installing.", Category: "Demo Software", Priority: "MEDIUM", Status: "INPROGRESS", CreatedBy: "demo.staff", AssignedTo: "demo.it"},
+	{Title: "[Demo] Wi-Fi drops in meeting room", Description: "Demo ticket: connection drops every few minutes.", Category: "Demo Network", Priority: "LOW", Status: "RESOLVED", CreatedBy: "demo.staff", AssignedTo: "demo.it"},
+}
+
+type SeedResult struct {
+	Divisions  int `json:"divisions"`
+	Categories int `json:"categories"`
+	Users      int `json:"users"`
+	Tickets    int `json:"tickets"`
+
+	// Passwords holds the generated password of each demo user created by
+	// this call, keyed by email. They are not stored in plain text anywhere.
+	Passwords map[string]string `json:"passwords,omitempty"`
+}
@@ -0,0 +1,154 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type Repository interface {
+	SeedDemo(ctx context.Context, passwordHashes map[string]string) (*SeedResult, []string, error)
+	DeleteDemo(ctx context.Context) (*SeedResult, error)
+}
+
+type repository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(db *sqlx.DB) Repository {
+	return &repository{db: db}
+}
+
+// SeedDemo also returns the usernames of the demo users it inserted; users
+// that already existed keep their password.
+func (r *repository) SeedDemo(ctx context.Context, passwordHashes map[string]string) (*SeedResult, []string, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result := &SeedResult{}
+
+	for _, name := range demoDivisions {
+		res, err := tx.ExecContext(ctx, `INSERT INTO divisions (name) VALUES ($1) ON CONFLICT (name) DO NOTHING`, name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to seed division: %w", err)
+		}
+		result.Divisions += rowsAffected(res)
+	}
+
+	for _, name := range demoCategories {
+		res, err := tx.ExecContext(ctx, `INSERT INTO categories (name) VALUES ($1) ON CONFLICT DO NOTHING`, name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to seed category: %w", err)
+		}
+		result.Categories += rowsAffected(res)
+	}
+
+	userQuery := `
+		INSERT INTO users (username, display_name, email, password, role, division_id)
+		SELECT $1, $2, $3, $4, $5, id FROM divisions WHERE name = $6
+		ON CONFLICT DO NOTHING
+	`
+	var created []string
+	for _, user := range demoUsers {
+		email := user.Username + "@" + DemoEmailDomain
+		res, err := tx.ExecContext(ctx, userQuery, user.Username, user.DisplayName, email, passwordHashes[user.Username], user.Role, user.Division)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to seed user: %w", err)
+		}
+		if rowsAffected(res) > 0 {
+			result.Users++
+			created = append(created, user.Username)
+		}
+	}
+
+	ticketQuery := `
+		INSERT INTO tickets (title, description, category_id, priority, status, created_by, assigned_to)
+		SELECT $1, $2, c.id, $3, $4, creator.id, assignee.id
+		FROM categories c
+		INNER JOIN users creator ON LOWER(creator.email) = LOWER($5)
+		LEFT JOIN users assignee ON LOWER(assignee.email) = LOWER($6)
+		WHERE c.name = $7
+			AND NOT EXISTS (SELECT 1 FROM tickets t WHERE t.title = $1 AND t.created_by = creator.id)
+	`
+	for _, ticket := range demoTickets {
+		creatorEmail := ticket.CreatedBy + "@" + DemoEmailDomain
+		assigneeEmail := ""
+		if ticket.AssignedTo != "" {
+			assigneeEmail = ticket.AssignedTo + "@" + DemoEmailDomain
+		}
+
+		res, err := tx.ExecContext(ctx, ticketQuery, ticket.Title, ticket.Description, ticket.Priority, ticket.Status, creatorEmail, assigneeEmail, ticket.Category)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to seed ticket: %w", err)
+		}
+		result.Tickets += rowsAffected(res)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit demo seed: %w", err)
+	}
+
+	return result, created, nil
+}
+
+func (r *repository) DeleteDemo(ctx context.Context) (*SeedResult, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result := &SeedResult{}
+	emailPattern := "%@" + DemoEmailDomain
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM tickets WHERE created_by IN (SELECT id FROM users WHERE email LIKE $1)`, emailPattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete demo tickets: %w", err)
+	}
+	result.Tickets = rowsAffected(res)
+
+	res, err = tx.ExecContext(ctx, `DELETE FROM users WHERE email LIKE $1`, emailPattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete demo users: %w", err)
+	}
+	result.Users = rowsAffected(res)
+
+	res, err = tx.ExecContext(ctx, `
+		DELETE FROM categories c
+		WHERE c.name = ANY($1) AND NOT EXISTS (SELECT 1 FROM tickets t WHERE t.category_id = c.id)
+	`, pq.Array(demoCategories))
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete demo categories: %w", err)
+	}
+	result.Categories = rowsAffected(res)
+
+	res, err = tx.ExecContext(ctx, `
+		DELETE FROM divisions d
+		WHERE d.name = ANY($1)
+			AND NOT EXISTS (SELECT 1 FROM users u WHERE u.division_id = d.id)
+	`, pq.Array(demoDivisions))
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete demo divisions: %w", err)
+	}
+	result.Divisions = rowsAffected(res)
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit demo cleanup: %w", err)
+	}
+
+	return result, nil
+}
+
+func rowsAffected(res sql.Result) int {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0
+	}
+	return int(n)
+}
@@ -0,0 +1,19 @@
+package admin
+
+import "github.com/labstack/echo/v5"
+
+func RegisterRoutes(g *echo.Group, handler *Handler, adminOnly echo.MiddlewareFunc, seedDemoEnabled bool) {
+	admin := g.Group("/admin", adminOnly)
+
+	admin.GET("/recent", handler.GetRecent)
+	admin.GET("/cache-stats", handler.GetCacheStats)
+	admin.DELETE("/cache", handler.ClearCaches)
+	admin.GET("/config", handler.GetConfig)
+	admin.GET("/read-only", handler.GetReadOnly)
+	admin.PUT("/read-only", handler.UpdateReadOnly)
+
+	if seedDemoEnabled {
+		admin.POST("/seed-demo", handler.SeedDemo)
+		admin.DELETE("/seed-demo", handler.DeleteDemo)
+	}
+}
@@ -0,0 +1,169 @@
+package admin
+
+import (
+	"context"
+	"log/slog"
+
+	"helpdesk/internal/config"
+	"helpdesk/internal/features/category"
+	"helpdesk/internal/features/division"
+	"helpdesk/internal/features/user"
+	appErrors "helpdesk/internal/utils/errors"
+	"helpdesk/internal/utils/response"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type Service interface {
+	GetRecent(ctx context.Context, req *GetRecentQuery) (*RecentResponse, error)
+	GetCacheStats() *CacheStatsResponse
+	GetConfig() *ConfigResponse
+	GetReadOnly() *ReadOnlyResponse
+	SeedDemo(ctx context.Context) (*SeedResult, error)
+	UpdateReadOnly(req *UpdateReadOnlyRequest) (*ReadOnlyResponse, error)
+	DeleteDemo(ctx context.Context) (*SeedResult, error)
+	ClearCaches() *CacheStatsResponse
+}
+
+type ReadOnlySwitch interface {
+	Enabled() bool
+	SetEnabled(enabled bool)
+}
+
+type service struct {
+	repo            Repository
+	userService     user.Service
+	categoryService category.Service
+	divisionService division.Service
+	readOnly        ReadOnlySwitch
+	config          *config.Config
+	logger          *slog.Logger
+}
+
+func NewService(repo Repository, userService user.Service, categoryService category.Service, divisionService division.Service, readOnly ReadOnlySwitch, cfg *config.Config, logger *slog.Logger) Service {
+	return &service{
+		repo:            repo,
+		userService:     userService,
+		categoryService: categoryService,
+		divisionService: divisionService,
+		readOnly:        readOnly,
+		config:          cfg,
+		logger:          logger,
+	}
+}
+
+func (s *service) GetRecent(ctx context.Context, req *GetRecentQuery) (*RecentResponse, error) {
+	limit, err := req.Normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	withTotal := false
+	pagination := response.PaginationQuery{Limit: limit, WithTotal: &withTotal}
+
+	users, err := s.userService.GetAll(ctx, &user.GetUsersQuery{PaginationQuery: pagination})
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.categoryService.GetAll(ctx, &category.GetCategoriesQuery{PaginationQuery: pagination})
+	if err != nil {
+		return nil, err
+	}
+
+	divisions, err := s.divisionService.GetAll(ctx, &division.GetDivisionsQuery{PaginationQuery: pagination})
+	if err != nil {
+		return nil, err
+	}
+
+	return &RecentResponse{
+		Users: toRecentItems(users.Items, func(u *user.UserResponse) RecentItem {
+			return RecentItem{ID: u.ID, Name: u.DisplayName, CreatedAt: u.CreatedAt}
+		}),
+		Categories: toRecentItems(categories.Items, func(c *category.CategoryResponse) RecentItem {
+			return RecentItem{ID: c.ID, Name: c.Name, CreatedAt: c.CreatedAt}
+		}),
+		Divisions: toRecentItems(divisions.Items, func(d *division.DivisionResponse) RecentItem {
+			return RecentItem{ID: d.ID, Name: d.Name, CreatedAt: d.CreatedAt}
+		}),
+	}, nil
+}
+
+func (s *service) GetCacheStats() *CacheStatsResponse {
+	return &CacheStatsResponse{
+		Categories: s.categoryService.GetCacheStats(),
+		Divisions:  s.divisionService.GetCacheStats(),
+	}
+}
+
+func (s *service) GetConfig() *ConfigResponse {
+	return toConfigResponse(s.config)
+}
+
+func (s *service) GetReadOnly() *ReadOnlyResponse {
+	return &ReadOnlyResponse{Enabled: s.readOnly.Enabled()}
+}
+
+func (s *service) SeedDemo(ctx context.Context) (*SeedResult, error) {
+	passwords := make(map[string]string, len(demoUsers))
+	passwordHashes := make(map[string]string, len(demoUsers))
+	for _, demoUser := range demoUsers {
+		password, err := user.GeneratePassword(s.config.GeneratedPasswordLength)
+		if err != nil {
+			return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to generate demo password", "Failed to seed demo data")
+		}
+
+		passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to hash demo password", "Failed to seed demo data")
+		}
+
+		passwords[demoUser.Username] = password
+		passwordHashes[demoUser.Username] = string(passwordHash)
+	}
+
+	result, created, err := s.repo.SeedDemo(ctx, passwordHashes)
+	if err != nil {
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to seed demo data", "Failed to seed demo data")
+	}
+
+	if len(created) > 0 {
+		result.Passwords = make(map[string]string, len(created))
+		for _, username := range created {
+			result.Passwords[username+"@"+DemoEmailDomain] = passwords[username]
+		}
+	}
+
+	s.logger.Info("demo data seeded", "divisions", result.Divisions, "categories", result.Categories, "users", result.Users, "tickets", result.Tickets)
+	return result, nil
+}
+
+func (s *service) UpdateReadOnly(req *UpdateReadOnlyRequest) (*ReadOnlyResponse, error) {
+	if err := req.Validate(); err != nil {
+		s.logger.Warn("validation failed", "error", err)
+		return nil, err
+	}
+
+	s.readOnly.SetEnabled(*req.Enabled)
+
+	s.logger.Warn("read-only mode changed", "enabled", *req.Enabled)
+	return s.GetReadOnly(), nil
+}
+
+func (s *service) ClearCaches() *CacheStatsResponse {
+	s.categoryService.ClearCache()
+	s.divisionService.ClearCache()
+
+	s.logger.Info("list caches cleared")
+	return s.GetCacheStats()
+}
+
+func (s *service) DeleteDemo(ctx context.Context) (*SeedResult, error) {
+	result, err := s.repo.DeleteDemo(ctx)
+	if err != nil {
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to delete demo data", "Failed to delete demo data")
+	}
+
+	s.logger.Info("demo data deleted", "divisions", result.Divisions, "categories", result.Categories, "users", result.Users, "tickets", result.Tickets)
+	return result, nil
+}
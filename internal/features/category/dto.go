@@ -1,16 +1,26 @@
 package category
 
 import (
+	"helpdesk/internal/utils/query"
 	"helpdesk/internal/utils/response"
 	"helpdesk/internal/utils/validator"
 	"strings"
 	"time"
 )
 
+var immutableCategoryFields = []string{"id", "createdAt"}
+
 type CreateCategoryRequest struct {
 	Name string `json:"name"`
 }
 
+// ImportCategoryRequest backs the admin-only import route, which keeps the
+// source system's createdAt instead of the insert time.
+type ImportCategoryRequest struct {
+	CreateCategoryRequest
+	CreatedAt *time.Time `json:"createdAt"`
+}
+
 type UpdateCategoryRequest struct {
 	Name     string `json:"name"`
 	IsActive *bool  `json:"isActive"`
@@ -28,21 +38,38 @@ type GetCategoriesQuery struct {
 	Name      string `query:"name"`
 	IsActive  *bool  `query:"isActive"`
 	CreatedAt string `query:"createdAt"`
+	Sort      string `query:"sort"`
 }
 
 type CategoryListFilter struct {
-	Page      int
-	Limit     int
-	Offset    int
+	response.Pagination
 	Name      string
 	IsActive  *bool
-	CreatedAt *time.Time
+	CreatedAt *response.DateRange
+	Sort      *query.Sort
 }
 
 func (r *CreateCategoryRequest) Validate() error {
 	v := validator.New()
 
+	r.validate(v)
+
+	if !v.Valid() {
+		return v.ToAppError()
+	}
+
+	return nil
+}
+
+func (r *CreateCategoryRequest) validate(v *validator.Validator) {
 	validator.ValidateString(v, "name", r.Name, true, 2, 20)
+}
+
+func (r *ImportCategoryRequest) Validate(now time.Time) error {
+	v := validator.New()
+
+	r.validate(v)
+	validator.ValidateBackdate(v, "createdAt", r.CreatedAt, now)
 
 	if !v.Valid() {
 		return v.ToAppError()
@@ -63,21 +90,28 @@ func (r *UpdateCategoryRequest) Validate() error {
 	return nil
 }
 
-func (q *GetCategoriesQuery) Normalize() (*CategoryListFilter, error) {
-	page, limit, offset := q.NormalizePagination()
+func (q *GetCategoriesQuery) Normalize(opts response.ListOptions) (*CategoryListFilter, error) {
+	pagination, err := q.NormalizePagination(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	createdAt, err := response.ParseDateFilter(q.CreatedAt, opts.Now())
+	if err != nil {
+		return nil, err
+	}
 
-	createdAt, err := response.ParseDate(q.CreatedAt)
+	sort, err := query.ParseSort(q.Sort, "name", "createdAt")
 	if err != nil {
 		return nil, err
 	}
 
 	return &CategoryListFilter{
-		Page:      page,
-		Limit:     limit,
-		Offset:    offset,
-		Name:      strings.TrimSpace(q.Name),
-		IsActive:  q.IsActive,
-		CreatedAt: createdAt,
+		Pagination: pagination,
+		Name:       strings.TrimSpace(q.Name),
+		IsActive:   q.IsActive,
+		CreatedAt:  createdAt,
+		Sort:       sort,
 	}, nil
 }
 
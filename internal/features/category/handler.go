@@ -48,6 +48,20 @@ func (h *Handler) GetByID(c *echo.Context) error {
 	return response.OK(c, "Category retrieved successfully", category)
 }
 
+func (h *Handler) ValidateIDs(c *echo.Context) error {
+	var req response.IDsRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, err)
+	}
+
+	results, err := h.service.ValidateIDs(c.Request().Context(), &req)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.OK(c, "Categories validated successfully", results)
+}
+
 func (h *Handler) Create(c *echo.Context) error {
 	var req CreateCategoryRequest
 
@@ -60,9 +74,45 @@ func (h *Handler) Create(c *echo.Context) error {
 		return response.Error(c, err)
 	}
 
+	response.SetLocation(c, "categories", category.ID)
 	return response.Created(c, "Category created successfully", category)
 }
 
+func (h *Handler) Import(c *echo.Context) error {
+	var req ImportCategoryRequest
+
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, err)
+	}
+
+	category, err := h.service.Import(c.Request().Context(), &req)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	response.SetLocation(c, "categories", category.ID)
+	return response.Created(c, "Category imported successfully", category)
+}
+
+func (h *Handler) Ensure(c *echo.Context) error {
+	var req CreateCategoryRequest
+
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, err)
+	}
+
+	category, created, err := h.service.EnsureByName(c.Request().Context(), req.Name)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	if created {
+		response.SetLocation(c, "categories", category.ID)
+		return response.Created(c, "Category created successfully", category)
+	}
+	return response.OK(c, "Category already exists", category)
+}
+
 func (h *Handler) Update(c *echo.Context) error {
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
@@ -71,7 +121,7 @@ func (h *Handler) Update(c *echo.Context) error {
 	}
 
 	var req UpdateCategoryRequest
-	if err := c.Bind(&req); err != nil {
+	if err := response.BindPatch(c, &req, immutableCategoryFields); err != nil {
 		return response.Error(c, err)
 	}
 
@@ -90,12 +140,23 @@ func (h *Handler) Delete(c *echo.Context) error {
 		return response.Error(c, errors.BadRequest("Invalid category ID"))
 	}
 
-	if err := h.service.Delete(c.Request().Context(), id); err != nil {
+	var req response.DeleteQuery
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Invalid query parameters"))
+	}
+
+	mode, err := h.service.Delete(c.Request().Context(), id, &req)
+	if err != nil {
 		return response.Error(c, err)
 	}
 
+	message := "Category deleted successfully"
+	if mode == response.DeleteModeSoft {
+		message = "Category deactivated successfully"
+	}
+
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"success": true,
-		"message": "Category deleted successfully",
+		"message": message,
 	})
 }
@@ -6,45 +6,69 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
+
+	"helpdesk/internal/utils/query"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 )
 
+var categorySortColumns = map[string]string{
+	"name":      "name",
+	"createdAt": "created_at",
+}
+
+// ErrInUse is returned when a category cannot be deleted because tickets
+// still reference it.
+var ErrInUse = errors.New("category is still referenced")
+
 type Repository interface {
 	GetAll(ctx context.Context, filter *CategoryListFilter) ([]Category, int, error)
 	GetByID(ctx context.Context, id int) (*Category, error)
 	GetByName(ctx context.Context, name string) (*Category, error)
+	GetByIDs(ctx context.Context, ids []int) ([]Category, error)
 	Exists(ctx context.Context, id int) (bool, error)
-	Create(ctx context.Context, name string) (*Category, error)
+	Create(ctx context.Context, name string, createdAt *time.Time) (*Category, error)
 	Update(ctx context.Context, id int, name string, isActive bool) (*Category, error)
+	Deactivate(ctx context.Context, id int) error
 	Delete(ctx context.Context, id int) error
 }
 
+// readDB serves list and stats queries that tolerate replica lag; it is the
+// same handle as db when no replica is configured.
 type repository struct {
-	db *sqlx.DB
+	db       *sqlx.DB
+	readDB   *sqlx.DB
+	timeouts query.Timeouts
 }
 
-func NewRepository(db *sqlx.DB) Repository {
-	return &repository{db: db}
+func NewRepository(db, readDB *sqlx.DB, timeouts query.Timeouts) Repository {
+	return &repository{db: db, readDB: readDB, timeouts: timeouts}
 }
 
 func (r *repository) GetAll(ctx context.Context, filter *CategoryListFilter) ([]Category, int, error) {
+	ctx, cancel := r.timeouts.WithTimeout(ctx, query.OpList)
+	defer cancel()
+
 	whereClause, args := buildCategoryFilterWhereClause(filter)
 
 	countQuery := `SELECT COUNT(*) FROM categories` + whereClause
 	var totalItems int
-	if err := r.db.GetContext(ctx, &totalItems, countQuery, args...); err != nil {
-		return nil, 0, fmt.Errorf("failed to count categories: %w", err)
+	if filter.WithTotal {
+		if err := r.readDB.GetContext(ctx, &totalItems, countQuery, args...); err != nil {
+			return nil, 0, fmt.Errorf("failed to count categories: %w", err)
+		}
 	}
 
 	limitPlaceholder := len(args) + 1
 	offsetPlaceholder := len(args) + 2
-	query := fmt.Sprintf(`SELECT id, name, is_active, created_at FROM categories%s ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d`, whereClause, limitPlaceholder, offsetPlaceholder)
+	orderBy := query.OrderBy(filter.Sort, categorySortColumns, "created_at DESC, id DESC", "id")
+	listQuery := fmt.Sprintf(`SELECT id, name, is_active, created_at FROM categories%s%s LIMIT $%d OFFSET $%d`, whereClause, orderBy, limitPlaceholder, offsetPlaceholder)
 	listArgs := append(args, filter.Limit, filter.Offset)
 
 	var categories []Category
-	err := r.db.SelectContext(ctx, &categories, query, listArgs...)
+	err := r.readDB.SelectContext(ctx, &categories, listQuery, listArgs...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get categories: %w", err)
 	}
@@ -86,6 +110,17 @@ func (r *repository) GetByName(ctx context.Context, name string) (*Category, err
 	return &category, nil
 }
 
+func (r *repository) GetByIDs(ctx context.Context, ids []int) ([]Category, error) {
+	query := `SELECT id, name, is_active, created_at FROM categories WHERE id = ANY($1) ORDER BY id`
+
+	var categories []Category
+	if err := r.db.SelectContext(ctx, &categories, query, pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+
+	return categories, nil
+}
+
 func (r *repository) Exists(ctx context.Context, id int) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM categories WHERE id = $1)`
 
@@ -98,11 +133,13 @@ func (r *repository) Exists(ctx context.Context, id int) (bool, error) {
 	return exists, nil
 }
 
-func (r *repository) Create(ctx context.Context, name string) (*Category, error) {
-	query := `INSERT INTO categories (name) VALUES ($1) RETURNING id, name, is_active, created_at`
+// Create inserts the category; a nil createdAt keeps the column default.
+func (r *repository) Create(ctx context.Context, name string, createdAt *time.Time) (*Category, error) {
+	createdAtArg := query.Timestamp(createdAt)
+	query := `INSERT INTO categories (name, created_at) VALUES ($1, COALESCE($2, CURRENT_TIMESTAMP)) RETURNING id, name, is_active, created_at`
 
 	var category Category
-	err := r.db.QueryRowxContext(ctx, query, name).StructScan(&category)
+	err := r.db.QueryRowxContext(ctx, query, name, createdAtArg).StructScan(&category)
 	if err != nil {
 		var pqErr *pq.Error
 		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
@@ -133,11 +170,35 @@ func (r *repository) Update(ctx context.Context, id int, name string, isActive b
 	return &category, nil
 }
 
+func (r *repository) Deactivate(ctx context.Context, id int) error {
+	query := `UPDATE categories SET is_active = FALSE WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to deactivate category: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
 func (r *repository) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM categories WHERE id = $1`
 
 	result, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return ErrInUse
+		}
 		return fmt.Errorf("failed to delete category: %w", err)
 	}
 
@@ -172,8 +233,8 @@ func buildCategoryFilterWhereClause(filter *CategoryListFilter) (string, []inter
 	}
 
 	if filter.CreatedAt != nil {
-		args = append(args, filter.CreatedAt.Format("2006-01-02"))
-		conditions = append(conditions, fmt.Sprintf("DATE(created_at) = $%d::date", len(args)))
+		args = append(args, filter.CreatedAt.From.UTC(), filter.CreatedAt.To.UTC())
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d AND created_at < $%d", len(args)-1, len(args)))
 	}
 
 	if len(conditions) == 0 {
@@ -2,12 +2,17 @@ package category
 
 import "github.com/labstack/echo/v5"
 
-func RegisterRoutes(g *echo.Group, handler *Handler) {
+// hardDeleteOnly guards DELETE when it resolves to a hard delete; soft
+// deletes stay open.
+func RegisterRoutes(g *echo.Group, handler *Handler, adminOnly, hardDeleteOnly echo.MiddlewareFunc) {
 	categories := g.Group("/categories")
 
 	categories.GET("", handler.GetAll)
 	categories.GET("/:id", handler.GetByID)
 	categories.POST("", handler.Create)
+	categories.POST("/validate", handler.ValidateIDs)
+	categories.POST("/import", handler.Import, adminOnly)
+	categories.POST("/ensure", handler.Ensure, adminOnly)
 	categories.PATCH("/:id", handler.Update)
-	categories.DELETE("/:id", handler.Delete)
+	categories.DELETE("/:id", handler.Delete, hardDeleteOnly)
 }
@@ -0,0 +1,43 @@
+package category
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"helpdesk/internal/middleware"
+
+	"github.com/labstack/echo/v5"
+)
+
+func TestEnsureRequiresAdminKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		wantStatus int
+	}{
+		{"missing key", "", http.StatusUnauthorized},
+		{"wrong key", "guess", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			adminOnly := middleware.AdminKey("operator-key")
+			RegisterRoutes(e.Group("/api/v1"), NewHandler(nil), adminOnly, adminOnly)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/categories/ensure", strings.NewReader(`{"name":"Hardware"}`))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			if tt.header != "" {
+				req.Header.Set("X-Admin-Key", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
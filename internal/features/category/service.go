@@ -3,11 +3,13 @@ package category
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
-	"fmt"
 	"log/slog"
 	"strings"
+	"time"
 
+	"helpdesk/internal/utils/cache"
 	appErrors "helpdesk/internal/utils/errors"
 	"helpdesk/internal/utils/response"
 )
@@ -15,20 +17,32 @@ import (
 type Service interface {
 	GetAll(ctx context.Context, req *GetCategoriesQuery) (*response.ListResponse[CategoryResponse], error)
 	GetByID(ctx context.Context, id int) (*CategoryResponse, error)
+	GetCacheStats() cache.Stats
+	ClearCache()
+	ValidateIDs(ctx context.Context, req *response.IDsRequest) (map[int]response.ExistenceResult, error)
 	Create(ctx context.Context, req *CreateCategoryRequest) (*CategoryResponse, error)
+	Import(ctx context.Context, req *ImportCategoryRequest) (*CategoryResponse, error)
+	EnsureByName(ctx context.Context, name string) (*CategoryResponse, bool, error)
 	Update(ctx context.Context, id int, req *UpdateCategoryRequest) (*CategoryResponse, error)
-	Delete(ctx context.Context, id int) error
+	Delete(ctx context.Context, id int, req *response.DeleteQuery) (string, error)
 }
 
 type service struct {
-	repo   Repository
-	logger *slog.Logger
+	repo        Repository
+	logger      *slog.Logger
+	listCache   *cache.TTLCache[*response.ListResponse[CategoryResponse]]
+	listOptions response.ListOptions
+	deleteMode  string
 }
 
-func NewService(repo Repository, logger *slog.Logger) Service {
+// deleteMode is what DELETE does without ?mode=: "hard" or "soft".
+func NewService(repo Repository, logger *slog.Logger, cacheTTL time.Duration, listOptions response.ListOptions, deleteMode string) Service {
 	return &service{
-		repo:   repo,
-		logger: logger,
+		repo:        repo,
+		logger:      logger,
+		listCache:   cache.New[*response.ListResponse[CategoryResponse]](cacheTTL),
+		listOptions: listOptions,
+		deleteMode:  deleteMode,
 	}
 }
 
@@ -37,26 +51,39 @@ func (s *service) GetAll(ctx context.Context, req *GetCategoriesQuery) (*respons
 		req = &GetCategoriesQuery{}
 	}
 
-	filter, err := req.Normalize()
+	filter, err := req.Normalize(s.listOptions)
 	if err != nil {
 		return nil, err
 	}
 
+	cacheKey := listCacheKey(filter)
+	if !filter.Unpaginated {
+		if cached, ok := s.listCache.Get(cacheKey); ok {
+			return cached.Clone(), nil
+		}
+	}
+
 	categories, totalItems, err := s.repo.GetAll(ctx, filter)
 	if err != nil {
-		s.logger.Error("failed to get categories", "error", err)
-		return nil, appErrors.Internal("Failed to retrieve categories")
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to get categories", "Failed to retrieve categories")
+	}
+
+	response.WarnIfTruncated(ctx, filter.Pagination, totalItems)
+
+	list := response.NewListResponse(ToCategoryResponses(categories), filter.Pagination, totalItems)
+	if !filter.Unpaginated {
+		s.listCache.Set(cacheKey, list.Clone())
 	}
 
-	return &response.ListResponse[CategoryResponse]{
-		Items: ToCategoryResponses(categories),
-		Pagination: response.PaginationResponse{
-			Page:       filter.Page,
-			Limit:      filter.Limit,
-			TotalItems: totalItems,
-			TotalPages: response.CalculateTotalPages(totalItems, filter.Limit),
-		},
-	}, nil
+	return list, nil
+}
+
+func (s *service) GetCacheStats() cache.Stats {
+	return s.listCache.Stats()
+}
+
+func (s *service) ClearCache() {
+	s.listCache.Clear()
 }
 
 func (s *service) GetByID(ctx context.Context, id int) (*CategoryResponse, error) {
@@ -66,8 +93,7 @@ func (s *service) GetByID(ctx context.Context, id int) (*CategoryResponse, error
 
 	category, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		s.logger.Error("failed to get category", "error", err, "id", id)
-		return nil, appErrors.Internal("Failed to retrieve category")
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to get category", "Failed to retrieve category", "id", id)
 	}
 
 	if category == nil {
@@ -77,36 +103,111 @@ func (s *service) GetByID(ctx context.Context, id int) (*CategoryResponse, error
 	return ToCategoryResponse(category), nil
 }
 
+func (s *service) ValidateIDs(ctx context.Context, req *response.IDsRequest) (map[int]response.ExistenceResult, error) {
+	if err := req.Validate(); err != nil {
+		s.logger.Warn("validation failed", "error", err)
+		return nil, err
+	}
+
+	categories, err := s.repo.GetByIDs(ctx, req.IDs)
+	if err != nil {
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to get categories by IDs", "Failed to validate categories")
+	}
+
+	results := make(map[int]response.ExistenceResult, len(req.IDs))
+	for _, id := range req.IDs {
+		results[id] = response.ExistenceResult{}
+	}
+	for _, item := range categories {
+		results[item.ID] = response.ExistenceResult{Exists: true, IsActive: item.IsActive}
+	}
+
+	return results, nil
+}
+
 func (s *service) Create(ctx context.Context, req *CreateCategoryRequest) (*CategoryResponse, error) {
 	if err := req.Validate(); err != nil {
 		s.logger.Warn("validation failed", "error", err)
 		return nil, err
 	}
 
-	name := strings.TrimSpace(req.Name)
+	return s.create(ctx, strings.TrimSpace(req.Name), nil)
+}
+
+// Import backs the admin-only import route: it creates the category with the
+// createdAt of the system it is migrated from.
+func (s *service) Import(ctx context.Context, req *ImportCategoryRequest) (*CategoryResponse, error) {
+	if err := req.Validate(time.Now()); err != nil {
+		s.logger.Warn("validation failed", "error", err)
+		return nil, err
+	}
+
+	return s.create(ctx, strings.TrimSpace(req.Name), req.CreatedAt)
+}
 
+func (s *service) create(ctx context.Context, name string, createdAt *time.Time) (*CategoryResponse, error) {
 	existing, err := s.repo.GetByName(ctx, name)
 	if err != nil {
-		s.logger.Error("failed to check existing category", "error", err)
-		return nil, appErrors.Internal("Failed to create category")
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to check existing category", "Failed to create category")
 	}
 	if existing != nil {
-		return nil, appErrors.AlreadyExists("Category")
+		return nil, appErrors.AlreadyExistsWithID("Category", existing.ID)
 	}
 
-	category, err := s.repo.Create(ctx, name)
+	category, err := s.repo.Create(ctx, name, createdAt)
 	if err != nil {
-		s.logger.Error("failed to create category", "error", err, "name", name)
 		if strings.Contains(err.Error(), "already exists") {
-			return nil, appErrors.AlreadyExists("Category")
+			return nil, s.alreadyExists(ctx, "Category", name)
 		}
-		return nil, appErrors.Internal("Failed to create category")
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to create category", "Failed to create category", "name", name)
 	}
 
-	s.logger.Info("category created", "id", category.ID, "name", category.Name)
+	s.listCache.Clear()
+	s.logger.Info("category created", "id", category.ID, "name", category.Name, "imported", createdAt != nil)
 	return ToCategoryResponse(category), nil
 }
 
+// EnsureByName returns the category with the given name (case-insensitive),
+// creating it when missing. The bool reports whether it was created.
+func (s *service) EnsureByName(ctx context.Context, name string) (*CategoryResponse, bool, error) {
+	req := CreateCategoryRequest{Name: name}
+	if err := req.Validate(); err != nil {
+		s.logger.Warn("validation failed", "error", err)
+		return nil, false, err
+	}
+
+	name = strings.TrimSpace(name)
+
+	existing, err := s.repo.GetByName(ctx, name)
+	if err != nil {
+		return nil, false, appErrors.FromRepository(ctx, s.logger, err, "failed to check existing category", "Failed to ensure category")
+	}
+	if existing != nil {
+		return ToCategoryResponse(existing), false, nil
+	}
+
+	category, err := s.repo.Create(ctx, name, nil)
+	if err != nil {
+		if !strings.Contains(err.Error(), "already exists") {
+			return nil, false, appErrors.FromRepository(ctx, s.logger, err, "failed to create category", "Failed to ensure category", "name", name)
+		}
+
+		// A concurrent request created it between the lookup and the insert.
+		existing, err = s.repo.GetByName(ctx, name)
+		if err != nil {
+			return nil, false, appErrors.FromRepository(ctx, s.logger, err, "failed to get category", "Failed to ensure category", "name", name)
+		}
+		if existing == nil {
+			return nil, false, appErrors.Conflict("Category was modified concurrently, retry the request")
+		}
+		return ToCategoryResponse(existing), false, nil
+	}
+
+	s.listCache.Clear()
+	s.logger.Info("category created", "id", category.ID, "name", category.Name, "ensure", true)
+	return ToCategoryResponse(category), true, nil
+}
+
 func (s *service) Update(ctx context.Context, id int, req *UpdateCategoryRequest) (*CategoryResponse, error) {
 	if id <= 0 {
 		return nil, appErrors.BadRequest("Invalid category ID")
@@ -119,8 +220,7 @@ func (s *service) Update(ctx context.Context, id int, req *UpdateCategoryRequest
 
 	exists, err := s.repo.Exists(ctx, id)
 	if err != nil {
-		s.logger.Error("failed to check category existence", "error", err, "id", id)
-		return nil, appErrors.Internal("Failed to update category")
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to check category existence", "Failed to update category", "id", id)
 	}
 	if !exists {
 		return nil, appErrors.NotFound("Category")
@@ -130,17 +230,15 @@ func (s *service) Update(ctx context.Context, id int, req *UpdateCategoryRequest
 
 	existing, err := s.repo.GetByName(ctx, name)
 	if err != nil {
-		s.logger.Error("failed to check existing category", "error", err)
-		return nil, appErrors.Internal("Failed to update category")
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to check existing category", "Failed to update category")
 	}
 	if existing != nil && existing.ID != id {
-		return nil, appErrors.AlreadyExists("Category with this name")
+		return nil, appErrors.AlreadyExistsWithID("Category with this name", existing.ID)
 	}
 
 	currentCategory, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		s.logger.Error("failed to get current category", "error", err, "id", id)
-		return nil, appErrors.Internal("Failed to update category")
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to get current category", "Failed to update category", "id", id)
 	}
 	if currentCategory == nil {
 		return nil, appErrors.NotFound("Category")
@@ -153,44 +251,76 @@ func (s *service) Update(ctx context.Context, id int, req *UpdateCategoryRequest
 
 	category, err := s.repo.Update(ctx, id, name, isActive)
 	if err != nil {
-		s.logger.Error("failed to update category", "error", err, "id", id)
 		if strings.Contains(err.Error(), "already exists") {
-			return nil, appErrors.AlreadyExists("Category")
+			return nil, s.alreadyExists(ctx, "Category with this name", name)
 		}
-		return nil, appErrors.Internal("Failed to update category")
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to update category", "Failed to update category", "id", id)
 	}
 
 	if category == nil {
 		return nil, appErrors.NotFound("Category")
 	}
 
+	s.listCache.Clear()
 	s.logger.Info("category updated", "id", category.ID, "name", category.Name)
 	return ToCategoryResponse(category), nil
 }
 
-func (s *service) Delete(ctx context.Context, id int) error {
+// Delete removes the category, or only deactivates it in soft mode so
+// existing references keep working. It returns the mode that was applied.
+func (s *service) Delete(ctx context.Context, id int, req *response.DeleteQuery) (string, error) {
 	if id <= 0 {
-		return appErrors.BadRequest("Invalid category ID")
+		return "", appErrors.BadRequest("Invalid category ID")
+	}
+
+	mode, err := req.Normalize(s.deleteMode)
+	if err != nil {
+		return "", err
 	}
 
 	exists, err := s.repo.Exists(ctx, id)
 	if err != nil {
-		s.logger.Error("failed to check category existence", "error", err, "id", id)
-		return appErrors.Internal("Failed to delete category")
+		return "", appErrors.FromRepository(ctx, s.logger, err, "failed to check category existence", "Failed to delete category", "id", id)
 	}
 	if !exists {
-		return appErrors.NotFound("Category")
+		return "", appErrors.NotFound("Category")
 	}
 
-	err = s.repo.Delete(ctx, id)
+	if mode == response.DeleteModeSoft {
+		err = s.repo.Deactivate(ctx, id)
+	} else {
+		err = s.repo.Delete(ctx, id)
+	}
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return appErrors.NotFound("Category")
+			return "", appErrors.NotFound("Category")
+		}
+		if errors.Is(err, ErrInUse) {
+			return "", appErrors.Conflict("Category is still used by tickets; use mode=soft to deactivate it instead")
 		}
-		s.logger.Error("failed to delete category", "error", err, "id", id)
-		return appErrors.Internal(fmt.Sprintf("Failed to delete category: %v", err))
+		return "", appErrors.FromRepository(ctx, s.logger, err, "failed to delete category", "Failed to delete category", "id", id)
 	}
 
-	s.logger.Info("category deleted", "id", id)
-	return nil
+	s.listCache.Clear()
+	s.logger.Info("category deleted", "id", id, "mode", mode)
+	return mode, nil
+}
+
+func listCacheKey(filter *CategoryListFilter) string {
+	key, _ := json.Marshal(filter)
+	return string(key)
+}
+
+// alreadyExists handles a unique-name violation from a concurrent write that
+// the lookup before it missed: it re-reads the winning row so the conflict
+// carries its ID like the non-racing path.
+func (s *service) alreadyExists(ctx context.Context, resource, name string) error {
+	existing, err := s.repo.GetByName(ctx, name)
+	if err != nil {
+		return appErrors.FromRepository(ctx, s.logger, err, "failed to get category", "Failed to save category", "name", name)
+	}
+	if existing == nil {
+		return appErrors.Conflict("Category was modified concurrently, retry the request")
+	}
+	return appErrors.AlreadyExistsWithID(resource, existing.ID)
 }
@@ -1,16 +1,26 @@
 package division
 
 import (
+	"helpdesk/internal/utils/query"
 	"helpdesk/internal/utils/response"
 	"helpdesk/internal/utils/validator"
 	"strings"
 	"time"
 )
 
+var immutableDivisionFields = []string{"id", "createdAt"}
+
 type CreateDivisionRequest struct {
 	Name string `json:"name"`
 }
 
+// ImportDivisionRequest backs the admin-only import route, which keeps the
+// source system's createdAt instead of the insert time.
+type ImportDivisionRequest struct {
+	CreateDivisionRequest
+	CreatedAt *time.Time `json:"createdAt"`
+}
+
 type UpdateDivisionRequest struct {
 	Name     string `json:"name"`
 	IsActive *bool  `json:"isActive"`
@@ -23,26 +33,51 @@ type DivisionResponse struct {
 	CreatedAt time.Time `json:"createdAt"`
 }
 
+type ITWorkloadResponse struct {
+	UserID      int    `json:"userId"`
+	Name        string `json:"name"`
+	Email       string `json:"email"`
+	IsAvailable bool   `json:"isAvailable"`
+	OpenTickets int    `json:"openTickets"`
+}
+
 type GetDivisionsQuery struct {
 	response.PaginationQuery
 	Name      string `query:"name"`
 	IsActive  *bool  `query:"isActive"`
 	CreatedAt string `query:"createdAt"`
+	Sort      string `query:"sort"`
 }
 
 type DivisionListFilter struct {
-	Page      int
-	Limit     int
-	Offset    int
+	response.Pagination
 	Name      string
 	IsActive  *bool
-	CreatedAt *time.Time
+	CreatedAt *response.DateRange
+	Sort      *query.Sort
 }
 
 func (r *CreateDivisionRequest) Validate() error {
 	v := validator.New()
 
+	r.validate(v)
+
+	if !v.Valid() {
+		return v.ToAppError()
+	}
+
+	return nil
+}
+
+func (r *CreateDivisionRequest) validate(v *validator.Validator) {
 	validator.ValidateString(v, "name", r.Name, true, 2, 50)
+}
+
+func (r *ImportDivisionRequest) Validate(now time.Time) error {
+	v := validator.New()
+
+	r.validate(v)
+	validator.ValidateBackdate(v, "createdAt", r.CreatedAt, now)
 
 	if !v.Valid() {
 		return v.ToAppError()
@@ -63,21 +98,28 @@ func (r *UpdateDivisionRequest) Validate() error {
 	return nil
 }
 
-func (q *GetDivisionsQuery) Normalize() (*DivisionListFilter, error) {
-	page, limit, offset := q.NormalizePagination()
+func (q *GetDivisionsQuery) Normalize(opts response.ListOptions) (*DivisionListFilter, error) {
+	pagination, err := q.NormalizePagination(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	createdAt, err := response.ParseDateFilter(q.CreatedAt, opts.Now())
+	if err != nil {
+		return nil, err
+	}
 
-	createdAt, err := response.ParseDate(q.CreatedAt)
+	sort, err := query.ParseSort(q.Sort, "name", "createdAt")
 	if err != nil {
 		return nil, err
 	}
 
 	return &DivisionListFilter{
-		Page:      page,
-		Limit:     limit,
-		Offset:    offset,
-		Name:      strings.TrimSpace(q.Name),
-		IsActive:  q.IsActive,
-		CreatedAt: createdAt,
+		Pagination: pagination,
+		Name:       strings.TrimSpace(q.Name),
+		IsActive:   q.IsActive,
+		CreatedAt:  createdAt,
+		Sort:       sort,
 	}, nil
 }
 
@@ -93,3 +135,17 @@ func ToDivisionResponse(d *Division) *DivisionResponse {
 func ToDivisionResponses(divisions []Division) []DivisionResponse {
 	return response.MapResponses(divisions, ToDivisionResponse)
 }
+
+func ToITWorkloadResponse(w *ITWorkload) *ITWorkloadResponse {
+	return &ITWorkloadResponse{
+		UserID:      w.UserID,
+		Name:        w.Name,
+		Email:       w.Email,
+		IsAvailable: w.IsAvailable,
+		OpenTickets: w.OpenTickets,
+	}
+}
+
+func ToITWorkloadResponses(workloads []ITWorkload) []ITWorkloadResponse {
+	return response.MapResponses(workloads, ToITWorkloadResponse)
+}
@@ -48,6 +48,50 @@ func (h *Handler) GetByID(c *echo.Context) error {
 	return response.OK(c, "Division retrieved successfully", division)
 }
 
+func (h *Handler) GetDistinctValues(c *echo.Context) error {
+	var req response.DistinctQuery
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Invalid query parameters"))
+	}
+
+	values, err := h.service.GetDistinctValues(c.Request().Context(), &req)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	response.SetCacheControl(c, response.DistinctMaxAge)
+	return response.OK(c, "Distinct values retrieved successfully", values)
+}
+
+func (h *Handler) GetITWorkload(c *echo.Context) error {
+	idParam := c.Param("id")
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		return response.Error(c, errors.BadRequest("Invalid division ID"))
+	}
+
+	workloads, err := h.service.GetITWorkload(c.Request().Context(), id)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.OK(c, "IT workload retrieved successfully", workloads)
+}
+
+func (h *Handler) ValidateIDs(c *echo.Context) error {
+	var req response.IDsRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, err)
+	}
+
+	results, err := h.service.ValidateIDs(c.Request().Context(), &req)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.OK(c, "Divisions validated successfully", results)
+}
+
 func (h *Handler) Create(c *echo.Context) error {
 	var req CreateDivisionRequest
 
@@ -60,9 +104,26 @@ func (h *Handler) Create(c *echo.Context) error {
 		return response.Error(c, err)
 	}
 
+	response.SetLocation(c, "divisions", division.ID)
 	return response.Created(c, "Division created successfully", division)
 }
 
+func (h *Handler) Import(c *echo.Context) error {
+	var req ImportDivisionRequest
+
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, err)
+	}
+
+	division, err := h.service.Import(c.Request().Context(), &req)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	response.SetLocation(c, "divisions", division.ID)
+	return response.Created(c, "Division imported successfully", division)
+}
+
 func (h *Handler) Update(c *echo.Context) error {
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
@@ -71,7 +132,7 @@ func (h *Handler) Update(c *echo.Context) error {
 	}
 
 	var req UpdateDivisionRequest
-	if err := c.Bind(&req); err != nil {
+	if err := response.BindPatch(c, &req, immutableDivisionFields); err != nil {
 		return response.Error(c, err)
 	}
 
@@ -90,12 +151,23 @@ func (h *Handler) Delete(c *echo.Context) error {
 		return response.Error(c, errors.BadRequest("Invalid division ID"))
 	}
 
-	if err := h.service.Delete(c.Request().Context(), id); err != nil {
+	var req response.DeleteQuery
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Invalid query parameters"))
+	}
+
+	mode, err := h.service.Delete(c.Request().Context(), id, &req)
+	if err != nil {
 		return response.Error(c, err)
 	}
 
+	message := "Division deleted successfully"
+	if mode == response.DeleteModeSoft {
+		message = "Division deactivated successfully"
+	}
+
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"success": true,
-		"message": "Division deleted successfully",
+		"message": message,
 	})
 }
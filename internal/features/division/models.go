@@ -2,9 +2,19 @@ package division
 
 import "time"
 
+var DistinctFields = []string{"isActive"}
+
 type Division struct {
 	ID        int       `db:"id" json:"id"`
 	Name      string    `db:"name" json:"name"`
 	IsActive  bool      `db:"is_active" json:"isActive"`
 	CreatedAt time.Time `db:"created_at" json:"createdAt"`
 }
+
+type ITWorkload struct {
+	UserID      int    `db:"user_id"`
+	Name        string `db:"name"`
+	Email       string `db:"email"`
+	IsAvailable bool   `db:"is_available"`
+	OpenTickets int    `db:"open_tickets"`
+}
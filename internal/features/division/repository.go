@@ -6,45 +6,75 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
+
+	"helpdesk/internal/utils/query"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 )
 
+var divisionSortColumns = map[string]string{
+	"name":      "name",
+	"createdAt": "created_at",
+}
+
+var divisionDistinctColumns = map[string]string{
+	"isActive": "is_active",
+}
+
+// ErrInUse is returned when a division cannot be deleted because users are
+// still assigned to it.
+var ErrInUse = errors.New("division is still referenced")
+
 type Repository interface {
 	GetAll(ctx context.Context, filter *DivisionListFilter) ([]Division, int, error)
 	GetByID(ctx context.Context, id int) (*Division, error)
 	GetByName(ctx context.Context, name string) (*Division, error)
+	GetByIDs(ctx context.Context, ids []int) ([]Division, error)
 	Exists(ctx context.Context, id int) (bool, error)
-	Create(ctx context.Context, name string) (*Division, error)
+	GetDistinctValues(ctx context.Context, field string) ([]any, error)
+	GetITWorkload(ctx context.Context, id int) ([]ITWorkload, error)
+	Create(ctx context.Context, name string, createdAt *time.Time) (*Division, error)
 	Update(ctx context.Context, id int, name string, isActive bool) (*Division, error)
+	Deactivate(ctx context.Context, id int) error
 	Delete(ctx context.Context, id int) error
 }
 
+// readDB serves list and stats queries that tolerate replica lag; it is the
+// same handle as db when no replica is configured.
 type repository struct {
-	db *sqlx.DB
+	db       *sqlx.DB
+	readDB   *sqlx.DB
+	timeouts query.Timeouts
 }
 
-func NewRepository(db *sqlx.DB) Repository {
-	return &repository{db: db}
+func NewRepository(db, readDB *sqlx.DB, timeouts query.Timeouts) Repository {
+	return &repository{db: db, readDB: readDB, timeouts: timeouts}
 }
 
 func (r *repository) GetAll(ctx context.Context, filter *DivisionListFilter) ([]Division, int, error) {
+	ctx, cancel := r.timeouts.WithTimeout(ctx, query.OpList)
+	defer cancel()
+
 	whereClause, args := buildDivisionFilterWhereClause(filter)
 
 	countQuery := `SELECT COUNT(*) FROM divisions` + whereClause
 	var totalItems int
-	if err := r.db.GetContext(ctx, &totalItems, countQuery, args...); err != nil {
-		return nil, 0, fmt.Errorf("failed to count divisions: %w", err)
+	if filter.WithTotal {
+		if err := r.readDB.GetContext(ctx, &totalItems, countQuery, args...); err != nil {
+			return nil, 0, fmt.Errorf("failed to count divisions: %w", err)
+		}
 	}
 
 	limitPlaceholder := len(args) + 1
 	offsetPlaceholder := len(args) + 2
-	query := fmt.Sprintf(`SELECT id, name, is_active, created_at FROM divisions%s ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d`, whereClause, limitPlaceholder, offsetPlaceholder)
+	orderBy := query.OrderBy(filter.Sort, divisionSortColumns, "created_at DESC, id DESC", "id")
+	listQuery := fmt.Sprintf(`SELECT id, name, is_active, created_at FROM divisions%s%s LIMIT $%d OFFSET $%d`, whereClause, orderBy, limitPlaceholder, offsetPlaceholder)
 	listArgs := append(args, filter.Limit, filter.Offset)
 
 	var divisions []Division
-	err := r.db.SelectContext(ctx, &divisions, query, listArgs...)
+	err := r.readDB.SelectContext(ctx, &divisions, listQuery, listArgs...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get divisions: %w", err)
 	}
@@ -86,6 +116,17 @@ func (r *repository) GetByName(ctx context.Context, name string) (*Division, err
 	return &division, nil
 }
 
+func (r *repository) GetByIDs(ctx context.Context, ids []int) ([]Division, error) {
+	query := `SELECT id, name, is_active, created_at FROM divisions WHERE id = ANY($1) ORDER BY id`
+
+	var divisions []Division
+	if err := r.db.SelectContext(ctx, &divisions, query, pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("failed to get divisions: %w", err)
+	}
+
+	return divisions, nil
+}
+
 func (r *repository) Exists(ctx context.Context, id int) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM divisions WHERE id = $1)`
 
@@ -98,11 +139,55 @@ func (r *repository) Exists(ctx context.Context, id int) (bool, error) {
 	return exists, nil
 }
 
-func (r *repository) Create(ctx context.Context, name string) (*Division, error) {
-	query := `INSERT INTO divisions (name) VALUES ($1) RETURNING id, name, is_active, created_at`
+func (r *repository) GetDistinctValues(ctx context.Context, field string) ([]any, error) {
+	column, ok := divisionDistinctColumns[field]
+	if !ok {
+		return nil, fmt.Errorf("unknown distinct field: %s", field)
+	}
+
+	ctx, cancel := r.timeouts.WithTimeout(ctx, query.OpDistinct)
+	defer cancel()
+
+	values, err := query.DistinctValues(ctx, r.readDB, "divisions", column)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get distinct division values: %w", err)
+	}
+
+	return values, nil
+}
+
+func (r *repository) GetITWorkload(ctx context.Context, id int) ([]ITWorkload, error) {
+	ctx, cancel := r.timeouts.WithTimeout(ctx, query.OpStats)
+	defer cancel()
+
+	query := `
+		SELECT u.id AS user_id, u.display_name AS name, u.email, u.is_available, COUNT(t.id) AS open_tickets 
+		FROM users u 
+		LEFT JOIN tickets t ON t.assigned_to = u.id AND t.status NOT IN ('RESOLVED', 'CLOSED') 
+		WHERE u.division_id = $1 AND u.role = 'IT' AND u.is_active = TRUE 
+		GROUP BY u.id, u.display_name, u.email, u.is_available 
+		ORDER BY u.is_available DESC, open_tickets ASC, u.id ASC
+	`
+
+	var workloads []ITWorkload
+	if err := r.readDB.SelectContext(ctx, &workloads, query, id); err != nil {
+		return nil, fmt.Errorf("failed to get IT workload: %w", err)
+	}
+
+	if workloads == nil {
+		workloads = []ITWorkload{}
+	}
+
+	return workloads, nil
+}
+
+// Create inserts the division; a nil createdAt keeps the column default.
+func (r *repository) Create(ctx context.Context, name string, createdAt *time.Time) (*Division, error) {
+	createdAtArg := query.Timestamp(createdAt)
+	query := `INSERT INTO divisions (name, created_at) VALUES ($1, COALESCE($2, CURRENT_TIMESTAMP)) RETURNING id, name, is_active, created_at`
 
 	var division Division
-	err := r.db.QueryRowxContext(ctx, query, name).StructScan(&division)
+	err := r.db.QueryRowxContext(ctx, query, name, createdAtArg).StructScan(&division)
 	if err != nil {
 		var pqErr *pq.Error
 		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
@@ -133,11 +218,35 @@ func (r *repository) Update(ctx context.Context, id int, name string, isActive b
 	return &division, nil
 }
 
+func (r *repository) Deactivate(ctx context.Context, id int) error {
+	query := `UPDATE divisions SET is_active = FALSE WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to deactivate division: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
 func (r *repository) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM divisions WHERE id = $1`
 
 	result, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return ErrInUse
+		}
 		return fmt.Errorf("failed to delete division: %w", err)
 	}
 
@@ -172,8 +281,8 @@ func buildDivisionFilterWhereClause(filter *DivisionListFilter) (string, []inter
 	}
 
 	if filter.CreatedAt != nil {
-		args = append(args, filter.CreatedAt.Format("2006-01-02"))
-		conditions = append(conditions, fmt.Sprintf("DATE(created_at) = $%d::date", len(args)))
+		args = append(args, filter.CreatedAt.From.UTC(), filter.CreatedAt.To.UTC())
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d AND created_at < $%d", len(args)-1, len(args)))
 	}
 
 	if len(conditions) == 0 {
@@ -0,0 +1,78 @@
+package division
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// failingConnector opens connections whose statements all fail with err, the
+// way lib/pq reports a constraint violation from the server.
+type failingConnector struct {
+	err error
+}
+
+func (c failingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return failingConn(c), nil
+}
+
+func (c failingConnector) Driver() driver.Driver {
+	return nil
+}
+
+type failingConn struct {
+	err error
+}
+
+func (c failingConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, c.err
+}
+
+func (c failingConn) Close() error {
+	return nil
+}
+
+func (c failingConn) Begin() (driver.Tx, error) {
+	return nil, c.err
+}
+
+func (c failingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return nil, c.err
+}
+
+func newFailingRepository(err error) Repository {
+	db := sqlx.NewDb(sql.OpenDB(failingConnector{err: err}), "postgres")
+	return NewRepository(db, db, nil)
+}
+
+func TestDeleteMapsForeignKeyViolationToErrInUse(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantInUse bool
+	}{
+		{"users foreign key", &pq.Error{Code: "23503", Constraint: "users_division_id_fkey"}, true},
+		{"query canceled", &pq.Error{Code: "57014"}, false},
+		{"connection error", errors.New("connection refused"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFailingRepository(tt.err)
+
+			err := repo.Delete(context.Background(), 1)
+
+			if got := errors.Is(err, ErrInUse); got != tt.wantInUse {
+				t.Fatalf("Delete() error = %v, want ErrInUse %v", err, tt.wantInUse)
+			}
+			if !tt.wantInUse && !errors.Is(err, tt.err) {
+				t.Errorf("Delete() error = %v, want it to wrap %v", err, tt.err)
+			}
+		})
+	}
+}
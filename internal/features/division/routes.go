@@ -2,12 +2,18 @@ package division
 
 import "github.com/labstack/echo/v5"
 
-func RegisterRoutes(g *echo.Group, handler *Handler) {
+// hardDeleteOnly guards DELETE when it resolves to a hard delete; soft
+// deletes stay open.
+func RegisterRoutes(g *echo.Group, handler *Handler, adminOnly, hardDeleteOnly echo.MiddlewareFunc) {
 	divisions := g.Group("/divisions")
 
 	divisions.GET("", handler.GetAll)
+	divisions.GET("/distinct", handler.GetDistinctValues)
 	divisions.GET("/:id", handler.GetByID)
+	divisions.GET("/:id/it-workload", handler.GetITWorkload)
 	divisions.POST("", handler.Create)
+	divisions.POST("/validate", handler.ValidateIDs)
+	divisions.POST("/import", handler.Import, adminOnly)
 	divisions.PATCH("/:id", handler.Update)
-	divisions.DELETE("/:id", handler.Delete)
+	divisions.DELETE("/:id", handler.Delete, hardDeleteOnly)
 }
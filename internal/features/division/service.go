@@ -3,11 +3,13 @@ package division
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
-	"fmt"
 	"log/slog"
 	"strings"
+	"time"
 
+	"helpdesk/internal/utils/cache"
 	appErrors "helpdesk/internal/utils/errors"
 	"helpdesk/internal/utils/response"
 )
@@ -15,21 +17,36 @@ import (
 type Service interface {
 	GetAll(ctx context.Context, req *GetDivisionsQuery) (*response.ListResponse[DivisionResponse], error)
 	GetByID(ctx context.Context, id int) (*DivisionResponse, error)
+	GetCacheStats() cache.Stats
+	ClearCache()
+	GetDistinctValues(ctx context.Context, req *response.DistinctQuery) (*response.DistinctValues, error)
+	GetITWorkload(ctx context.Context, id int) ([]ITWorkloadResponse, error)
+	ValidateIDs(ctx context.Context, req *response.IDsRequest) (map[int]response.ExistenceResult, error)
 	ValidateForAssignment(ctx context.Context, id int) error
 	Create(ctx context.Context, req *CreateDivisionRequest) (*DivisionResponse, error)
+	Import(ctx context.Context, req *ImportDivisionRequest) (*DivisionResponse, error)
 	Update(ctx context.Context, id int, req *UpdateDivisionRequest) (*DivisionResponse, error)
-	Delete(ctx context.Context, id int) error
+	Delete(ctx context.Context, id int, req *response.DeleteQuery) (string, error)
 }
 
 type service struct {
-	repo   Repository
-	logger *slog.Logger
+	repo          Repository
+	logger        *slog.Logger
+	requireActive bool
+	listCache     *cache.TTLCache[*response.ListResponse[DivisionResponse]]
+	listOptions   response.ListOptions
+	deleteMode    string
 }
 
-func NewService(repo Repository, logger *slog.Logger) Service {
+// deleteMode is what DELETE does without ?mode=: "hard" or "soft".
+func NewService(repo Repository, logger *slog.Logger, requireActive bool, cacheTTL time.Duration, listOptions response.ListOptions, deleteMode string) Service {
 	return &service{
-		repo:   repo,
-		logger: logger,
+		repo:          repo,
+		logger:        logger,
+		requireActive: requireActive,
+		listCache:     cache.New[*response.ListResponse[DivisionResponse]](cacheTTL),
+		listOptions:   listOptions,
+		deleteMode:    deleteMode,
 	}
 }
 
@@ -38,26 +55,39 @@ func (s *service) GetAll(ctx context.Context, req *GetDivisionsQuery) (*response
 		req = &GetDivisionsQuery{}
 	}
 
-	filter, err := req.Normalize()
+	filter, err := req.Normalize(s.listOptions)
 	if err != nil {
 		return nil, err
 	}
 
+	cacheKey := listCacheKey(filter)
+	if !filter.Unpaginated {
+		if cached, ok := s.listCache.Get(cacheKey); ok {
+			return cached.Clone(), nil
+		}
+	}
+
 	divisions, totalItems, err := s.repo.GetAll(ctx, filter)
 	if err != nil {
-		s.logger.Error("failed to get divisions", "error", err)
-		return nil, appErrors.Internal("Failed to retrieve divisions")
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to get divisions", "Failed to retrieve divisions")
 	}
 
-	return &response.ListResponse[DivisionResponse]{
-		Items: ToDivisionResponses(divisions),
-		Pagination: response.PaginationResponse{
-			Page:       filter.Page,
-			Limit:      filter.Limit,
-			TotalItems: totalItems,
-			TotalPages: response.CalculateTotalPages(totalItems, filter.Limit),
-		},
-	}, nil
+	response.WarnIfTruncated(ctx, filter.Pagination, totalItems)
+
+	list := response.NewListResponse(ToDivisionResponses(divisions), filter.Pagination, totalItems)
+	if !filter.Unpaginated {
+		s.listCache.Set(cacheKey, list.Clone())
+	}
+
+	return list, nil
+}
+
+func (s *service) GetCacheStats() cache.Stats {
+	return s.listCache.Stats()
+}
+
+func (s *service) ClearCache() {
+	s.listCache.Clear()
 }
 
 func (s *service) GetByID(ctx context.Context, id int) (*DivisionResponse, error) {
@@ -67,8 +97,7 @@ func (s *service) GetByID(ctx context.Context, id int) (*DivisionResponse, error
 
 	division, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		s.logger.Error("failed to get division", "error", err, "id", id)
-		return nil, appErrors.Internal("Failed to retrieve division")
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to get division", "Failed to retrieve division", "id", id)
 	}
 
 	if division == nil {
@@ -78,6 +107,19 @@ func (s *service) GetByID(ctx context.Context, id int) (*DivisionResponse, error
 	return ToDivisionResponse(division), nil
 }
 
+func (s *service) GetDistinctValues(ctx context.Context, req *response.DistinctQuery) (*response.DistinctValues, error) {
+	if err := req.Validate(DistinctFields); err != nil {
+		return nil, err
+	}
+
+	values, err := s.repo.GetDistinctValues(ctx, req.Field)
+	if err != nil {
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to get distinct division values", "Failed to retrieve distinct values", "field", req.Field)
+	}
+
+	return &response.DistinctValues{Field: req.Field, Values: values}, nil
+}
+
 func (s *service) ValidateForAssignment(ctx context.Context, id int) error {
 	if id <= 0 {
 		return appErrors.BadRequest("Invalid division ID")
@@ -85,8 +127,7 @@ func (s *service) ValidateForAssignment(ctx context.Context, id int) error {
 
 	division, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		s.logger.Error("failed to get division", "error", err, "id", id)
-		return appErrors.Internal("Failed to validate division")
+		return appErrors.FromRepository(ctx, s.logger, err, "failed to get division", "Failed to validate division", "id", id)
 	}
 
 	if division == nil {
@@ -94,39 +135,98 @@ func (s *service) ValidateForAssignment(ctx context.Context, id int) error {
 	}
 
 	if !division.IsActive {
-		return appErrors.BadRequest("Division is not active")
+		if s.requireActive {
+			return appErrors.BadRequest("Division is not active")
+		}
+		s.logger.Warn("assigning inactive division", "id", id)
+		response.AddWarning(ctx, response.WarningInactiveDivision, "Division is not active")
 	}
 
 	return nil
 }
 
+func (s *service) GetITWorkload(ctx context.Context, id int) ([]ITWorkloadResponse, error) {
+	if id <= 0 {
+		return nil, appErrors.BadRequest("Invalid division ID")
+	}
+
+	exists, err := s.repo.Exists(ctx, id)
+	if err != nil {
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to check division existence", "Failed to retrieve IT workload", "id", id)
+	}
+	if !exists {
+		return nil, appErrors.NotFound("Division")
+	}
+
+	workloads, err := s.repo.GetITWorkload(ctx, id)
+	if err != nil {
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to get IT workload", "Failed to retrieve IT workload", "id", id)
+	}
+
+	return ToITWorkloadResponses(workloads), nil
+}
+
+func (s *service) ValidateIDs(ctx context.Context, req *response.IDsRequest) (map[int]response.ExistenceResult, error) {
+	if err := req.Validate(); err != nil {
+		s.logger.Warn("validation failed", "error", err)
+		return nil, err
+	}
+
+	divisions, err := s.repo.GetByIDs(ctx, req.IDs)
+	if err != nil {
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to get divisions by IDs", "Failed to validate divisions")
+	}
+
+	results := make(map[int]response.ExistenceResult, len(req.IDs))
+	for _, id := range req.IDs {
+		results[id] = response.ExistenceResult{}
+	}
+	for _, item := range divisions {
+		results[item.ID] = response.ExistenceResult{Exists: true, IsActive: item.IsActive}
+	}
+
+	return results, nil
+}
+
 func (s *service) Create(ctx context.Context, req *CreateDivisionRequest) (*DivisionResponse, error) {
 	if err := req.Validate(); err != nil {
 		s.logger.Warn("validation failed", "error", err)
 		return nil, err
 	}
 
-	name := strings.TrimSpace(req.Name)
+	return s.create(ctx, strings.TrimSpace(req.Name), nil)
+}
+
+// Import backs the admin-only import route: it creates the division with the
+// createdAt of the system it is migrated from.
+func (s *service) Import(ctx context.Context, req *ImportDivisionRequest) (*DivisionResponse, error) {
+	if err := req.Validate(time.Now()); err != nil {
+		s.logger.Warn("validation failed", "error", err)
+		return nil, err
+	}
 
+	return s.create(ctx, strings.TrimSpace(req.Name), req.CreatedAt)
+}
+
+func (s *service) create(ctx context.Context, name string, createdAt *time.Time) (*DivisionResponse, error) {
 	existing, err := s.repo.GetByName(ctx, name)
 	if err != nil {
-		s.logger.Error("failed to check existing division", "error", err)
-		return nil, appErrors.Internal("Failed to create division")
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to check existing division", "Failed to create division")
 	}
 	if existing != nil {
-		return nil, appErrors.AlreadyExists("Division")
+		return nil, appErrors.AlreadyExistsWithID("Division", existing.ID)
 	}
 
-	division, err := s.repo.Create(ctx, name)
+	division, err := s.repo.Create(ctx, name, createdAt)
 	if err != nil {
-		s.logger.Error("failed to create division", "error", err, "name", name)
 		if strings.Contains(err.Error(), "already exists") {
-			return nil, appErrors.AlreadyExists("Division")
+			return nil, s.alreadyExists(ctx, "Division", name)
 		}
-		return nil, appErrors.Internal("Failed to create division")
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to create division", "Failed to create division", "name", name)
 	}
 
-	s.logger.Info("division created", "id", division.ID, "name", division.Name)
+	s.listCache.Clear()
+	s.logger.Info("division created", "id", division.ID, "name", division.Name, "imported", createdAt != nil)
 	return ToDivisionResponse(division), nil
 }
 
@@ -142,8 +242,7 @@ func (s *service) Update(ctx context.Context, id int, req *UpdateDivisionRequest
 
 	exists, err := s.repo.Exists(ctx, id)
 	if err != nil {
-		s.logger.Error("failed to check division existence", "error", err, "id", id)
-		return nil, appErrors.Internal("Failed to update division")
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to check division existence", "Failed to update division", "id", id)
 	}
 	if !exists {
 		return nil, appErrors.NotFound("Division")
@@ -153,17 +252,15 @@ func (s *service) Update(ctx context.Context, id int, req *UpdateDivisionRequest
 
 	existing, err := s.repo.GetByName(ctx, name)
 	if err != nil {
-		s.logger.Error("failed to check existing division", "error", err)
-		return nil, appErrors.Internal("Failed to update division")
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to check existing division", "Failed to update division")
 	}
 	if existing != nil && existing.ID != id {
-		return nil, appErrors.AlreadyExists("Division with this name")
+		return nil, appErrors.AlreadyExistsWithID("Division with this name", existing.ID)
 	}
 
 	currentDivision, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		s.logger.Error("failed to get current division", "error", err, "id", id)
-		return nil, appErrors.Internal("Failed to update division")
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to get current division", "Failed to update division", "id", id)
 	}
 	if currentDivision == nil {
 		return nil, appErrors.NotFound("Division")
@@ -176,44 +273,76 @@ func (s *service) Update(ctx context.Context, id int, req *UpdateDivisionRequest
 
 	division, err := s.repo.Update(ctx, id, name, isActive)
 	if err != nil {
-		s.logger.Error("failed to update division", "error", err, "id", id)
 		if strings.Contains(err.Error(), "already exists") {
-			return nil, appErrors.AlreadyExists("Division")
+			return nil, s.alreadyExists(ctx, "Division with this name", name)
 		}
-		return nil, appErrors.Internal("Failed to update division")
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to update division", "Failed to update division", "id", id)
 	}
 
 	if division == nil {
 		return nil, appErrors.NotFound("Division")
 	}
 
+	s.listCache.Clear()
 	s.logger.Info("division updated", "id", division.ID, "name", division.Name)
 	return ToDivisionResponse(division), nil
 }
 
-func (s *service) Delete(ctx context.Context, id int) error {
+// Delete removes the division, or only deactivates it in soft mode so
+// existing references keep working. It returns the mode that was applied.
+func (s *service) Delete(ctx context.Context, id int, req *response.DeleteQuery) (string, error) {
 	if id <= 0 {
-		return appErrors.BadRequest("Invalid division ID")
+		return "", appErrors.BadRequest("Invalid division ID")
+	}
+
+	mode, err := req.Normalize(s.deleteMode)
+	if err != nil {
+		return "", err
 	}
 
 	exists, err := s.repo.Exists(ctx, id)
 	if err != nil {
-		s.logger.Error("failed to check division existence", "error", err, "id", id)
-		return appErrors.Internal("Failed to delete division")
+		return "", appErrors.FromRepository(ctx, s.logger, err, "failed to check division existence", "Failed to delete division", "id", id)
 	}
 	if !exists {
-		return appErrors.NotFound("Division")
+		return "", appErrors.NotFound("Division")
 	}
 
-	err = s.repo.Delete(ctx, id)
+	if mode == response.DeleteModeSoft {
+		err = s.repo.Deactivate(ctx, id)
+	} else {
+		err = s.repo.Delete(ctx, id)
+	}
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return appErrors.NotFound("Division")
+			return "", appErrors.NotFound("Division")
+		}
+		if errors.Is(err, ErrInUse) {
+			return "", appErrors.Conflict("Division is still assigned to users; use mode=soft to deactivate it instead")
 		}
-		s.logger.Error("failed to delete division", "error", err, "id", id)
-		return appErrors.Internal(fmt.Sprintf("Failed to delete division: %v", err))
+		return "", appErrors.FromRepository(ctx, s.logger, err, "failed to delete division", "Failed to delete division", "id", id)
 	}
 
-	s.logger.Info("division deleted", "id", id)
-	return nil
+	s.listCache.Clear()
+	s.logger.Info("division deleted", "id", id, "mode", mode)
+	return mode, nil
+}
+
+func listCacheKey(filter *DivisionListFilter) string {
+	key, _ := json.Marshal(filter)
+	return string(key)
+}
+
+// alreadyExists handles a unique-name violation from a concurrent write that
+// the lookup before it missed: it re-reads the winning row so the conflict
+// carries its ID like the non-racing path.
+func (s *service) alreadyExists(ctx context.Context, resource, name string) error {
+	existing, err := s.repo.GetByName(ctx, name)
+	if err != nil {
+		return appErrors.FromRepository(ctx, s.logger, err, "failed to get division", "Failed to save division", "name", name)
+	}
+	if existing == nil {
+		return appErrors.Conflict("Division was modified concurrently, retry the request")
+	}
+	return appErrors.AlreadyExistsWithID(resource, existing.ID)
 }
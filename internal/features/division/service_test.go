@@ -0,0 +1,120 @@
+package division
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	appErrors "helpdesk/internal/utils/errors"
+	"helpdesk/internal/utils/response"
+)
+
+// fakeRepository models a division still referenced by a user: its Delete
+// returns ErrInUse, which repository_test.go checks the real repository maps
+// the users foreign key violation to.
+type fakeRepository struct {
+	Repository
+	divisions map[int]*Division
+	assigned  map[int]bool
+	// raced is inserted by the next Create, which then fails the way the
+	// unique name index does when a concurrent request won.
+	raced *Division
+}
+
+func (r *fakeRepository) GetByName(ctx context.Context, name string) (*Division, error) {
+	for _, division := range r.divisions {
+		if strings.EqualFold(division.Name, name) {
+			copied := *division
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeRepository) Exists(ctx context.Context, id int) (bool, error) {
+	_, ok := r.divisions[id]
+	return ok, nil
+}
+
+func (r *fakeRepository) Create(ctx context.Context, name string, createdAt *time.Time) (*Division, error) {
+	if r.raced != nil {
+		r.divisions[r.raced.ID] = r.raced
+		r.raced = nil
+		return nil, fmt.Errorf("division with name '%s' already exists", name)
+	}
+	division := &Division{ID: len(r.divisions) + 1, Name: name, IsActive: true}
+	r.divisions[division.ID] = division
+	return division, nil
+}
+
+func (r *fakeRepository) Deactivate(ctx context.Context, id int) error {
+	r.divisions[id].IsActive = false
+	return nil
+}
+
+func (r *fakeRepository) Delete(ctx context.Context, id int) error {
+	if r.assigned[id] {
+		return ErrInUse
+	}
+	delete(r.divisions, id)
+	return nil
+}
+
+func newTestService() (Service, *fakeRepository) {
+	repo := &fakeRepository{
+		divisions: map[int]*Division{1: {ID: 1, Name: "IT Support", IsActive: true}},
+		assigned:  map[int]bool{1: true},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewService(repo, logger, true, 0, response.ListOptions{}, response.DeleteModeHard), repo
+}
+
+func TestDeleteDivisionInUseIsConflict(t *testing.T) {
+	svc, repo := newTestService()
+
+	_, err := svc.Delete(context.Background(), 1, &response.DeleteQuery{Mode: response.DeleteModeHard})
+
+	var appErr *appErrors.AppError
+	if !errors.As(err, &appErr) || appErr.StatusCode != http.StatusConflict {
+		t.Fatalf("Delete() error = %v, want 409 conflict", err)
+	}
+	if _, ok := repo.divisions[1]; !ok {
+		t.Error("division was deleted while a user is still assigned to it")
+	}
+}
+
+func TestSoftDeleteDivisionInUseDeactivates(t *testing.T) {
+	svc, repo := newTestService()
+
+	mode, err := svc.Delete(context.Background(), 1, &response.DeleteQuery{Mode: response.DeleteModeSoft})
+	if err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if mode != response.DeleteModeSoft {
+		t.Errorf("mode = %q, want %q", mode, response.DeleteModeSoft)
+	}
+	if division, ok := repo.divisions[1]; !ok || division.IsActive {
+		t.Errorf("division = %+v, want it kept and inactive", division)
+	}
+}
+
+func TestCreateRacedByNameReturnsExistingID(t *testing.T) {
+	svc, repo := newTestService()
+	repo.raced = &Division{ID: 5, Name: "Finance", IsActive: true}
+
+	_, err := svc.Create(context.Background(), &CreateDivisionRequest{Name: "Finance"})
+
+	var appErr *appErrors.AppError
+	if !errors.As(err, &appErr) || appErr.StatusCode != http.StatusConflict {
+		t.Fatalf("Create() error = %v, want 409", err)
+	}
+	if got := appErr.Details["existingId"]; got != 5 {
+		t.Errorf("details.existingId = %v, want 5", got)
+	}
+}
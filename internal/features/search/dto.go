@@ -0,0 +1,81 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+
+	"helpdesk/internal/features/category"
+	"helpdesk/internal/features/division"
+	"helpdesk/internal/features/user"
+	"helpdesk/internal/utils/response"
+	"helpdesk/internal/utils/validator"
+)
+
+const (
+	TypeUsers      = "users"
+	TypeCategories = "categories"
+	TypeDivisions  = "divisions"
+)
+
+var ValidTypes = []string{TypeUsers, TypeCategories, TypeDivisions}
+
+const (
+	DefaultLimit = 5
+	MaxLimit     = 20
+)
+
+type SearchQuery struct {
+	Q     string `query:"q"`
+	Types string `query:"types"`
+	Page  int    `query:"page"`
+	Limit int    `query:"limit"`
+}
+
+type SearchFilter struct {
+	Q          string
+	Types      map[string]bool
+	Pagination response.PaginationQuery
+}
+
+type SearchResponse struct {
+	Users      *response.ListResponse[user.UserResponse]         `json:"users,omitempty"`
+	Categories *response.ListResponse[category.CategoryResponse] `json:"categories,omitempty"`
+	Divisions  *response.ListResponse[division.DivisionResponse] `json:"divisions,omitempty"`
+}
+
+func (q *SearchQuery) Normalize() (*SearchFilter, error) {
+	v := validator.New()
+
+	term := strings.Join(strings.Fields(q.Q), " ")
+	validator.ValidateString(v, "q", term, true, 1, 100)
+
+	types := make(map[string]bool)
+	if strings.TrimSpace(q.Types) == "" {
+		for _, t := range ValidTypes {
+			types[t] = true
+		}
+	} else {
+		for _, t := range strings.Split(q.Types, ",") {
+			t = strings.TrimSpace(t)
+			validator.ValidateEnum(v, "types", t, ValidTypes, true)
+			types[t] = true
+		}
+	}
+
+	limit := q.Limit
+	if limit == 0 {
+		limit = DefaultLimit
+	}
+	v.Check(limit > 0 && limit <= MaxLimit, "limit", fmt.Sprintf("limit must be between 1 and %d", MaxLimit))
+	v.Check(q.Page >= 0, "page", "page must not be negative")
+
+	if !v.Valid() {
+		return nil, v.ToAppError()
+	}
+
+	return &SearchFilter{
+		Q:          term,
+		Types:      types,
+		Pagination: response.PaginationQuery{Page: q.Page, Limit: limit},
+	}, nil
+}
@@ -0,0 +1,32 @@
+package search
+
+import (
+	"helpdesk/internal/utils/errors"
+	"helpdesk/internal/utils/response"
+
+	"github.com/labstack/echo/v5"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+func (h *Handler) Search(c *echo.Context) error {
+	var req SearchQuery
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Invalid query parameters"))
+	}
+
+	results, err := h.service.Search(c.Request().Context(), &req)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.OK(c, "Search completed successfully", results)
+}
@@ -0,0 +1,7 @@
+package search
+
+import "github.com/labstack/echo/v5"
+
+func RegisterRoutes(g *echo.Group, handler *Handler) {
+	g.GET("/search", handler.Search)
+}
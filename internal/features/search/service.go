@@ -0,0 +1,71 @@
+package search
+
+import (
+	"context"
+	"log/slog"
+
+	"helpdesk/internal/features/category"
+	"helpdesk/internal/features/division"
+	"helpdesk/internal/features/user"
+	"helpdesk/internal/utils/query"
+)
+
+type Service interface {
+	Search(ctx context.Context, req *SearchQuery) (*SearchResponse, error)
+}
+
+type service struct {
+	userService     user.Service
+	categoryService category.Service
+	divisionService division.Service
+	logger          *slog.Logger
+	timeouts        query.Timeouts
+}
+
+func NewService(userService user.Service, categoryService category.Service, divisionService division.Service, logger *slog.Logger, timeouts query.Timeouts) Service {
+	return &service{
+		userService:     userService,
+		categoryService: categoryService,
+		divisionService: divisionService,
+		logger:          logger,
+		timeouts:        timeouts,
+	}
+}
+
+// Search runs the name search of each requested type with the same page and
+// limit, so each group paginates independently of the others. The search
+// timeout covers all groups together.
+func (s *service) Search(ctx context.Context, req *SearchQuery) (*SearchResponse, error) {
+	filter, err := req.Normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := s.timeouts.WithTimeout(ctx, query.OpSearch)
+	defer cancel()
+
+	result := &SearchResponse{}
+
+	if filter.Types[TypeUsers] {
+		result.Users, err = s.userService.GetAll(ctx, &user.GetUsersQuery{PaginationQuery: filter.Pagination, Name: filter.Q})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if filter.Types[TypeCategories] {
+		result.Categories, err = s.categoryService.GetAll(ctx, &category.GetCategoriesQuery{PaginationQuery: filter.Pagination, Name: filter.Q})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if filter.Types[TypeDivisions] {
+		result.Divisions, err = s.divisionService.GetAll(ctx, &division.GetDivisionsQuery{PaginationQuery: filter.Pagination, Name: filter.Q})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
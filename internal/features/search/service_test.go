@@ -0,0 +1,49 @@
+package search
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"helpdesk/internal/features/user"
+	"helpdesk/internal/utils/query"
+	"helpdesk/internal/utils/response"
+)
+
+type fakeUserService struct {
+	user.Service
+	hasDeadline bool
+}
+
+func (s *fakeUserService) GetAll(ctx context.Context, req *user.GetUsersQuery) (*response.ListResponse[user.UserResponse], error) {
+	_, s.hasDeadline = ctx.Deadline()
+	return &response.ListResponse[user.UserResponse]{Items: []user.UserResponse{}}, nil
+}
+
+func TestSearchAppliesSearchTimeout(t *testing.T) {
+	tests := []struct {
+		name         string
+		timeouts     query.Timeouts
+		wantDeadline bool
+	}{
+		{"search timeout set", query.Timeouts{query.OpSearch: time.Second}, true},
+		{"search timeout unset", query.Timeouts{query.OpList: time.Second}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := &fakeUserService{}
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			svc := NewService(users, nil, nil, logger, tt.timeouts)
+
+			if _, err := svc.Search(context.Background(), &SearchQuery{Q: "ana", Types: TypeUsers}); err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if users.hasDeadline != tt.wantDeadline {
+				t.Errorf("user search has deadline = %v, want %v", users.hasDeadline, tt.wantDeadline)
+			}
+		})
+	}
+}
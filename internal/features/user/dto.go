@@ -1,38 +1,118 @@
 package user
 
 import (
+	"fmt"
+	"hash/fnv"
+	"helpdesk/internal/utils/nullable"
+	"helpdesk/internal/utils/query"
 	"helpdesk/internal/utils/response"
 	"helpdesk/internal/utils/validator"
+	"math"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 )
 
+const (
+	MaxCheckEmails           = 100
+	MaxExistsLookups         = 100
+	MaxDivisionUnchangedDays = 36500
+)
+
+var immutableUserFields = []string{"id", "email", "createdAt"}
+
+var avatarColors = []string{
+	"#E57373", "#F06292", "#BA68C8", "#9575CD",
+	"#7986CB", "#64B5F6", "#4FC3F7", "#4DB6AC",
+	"#81C784", "#AED581", "#FFB74D", "#A1887F",
+}
+
 type CreateUserRequest struct {
-	Name       string `json:"name"`
-	Email      string `json:"email"`
-	Password   string `json:"password"`
-	Role       string `json:"role"`
-	DivisionID int    `json:"divisionId"`
+	Username    string `json:"username"`
+	DisplayName string `json:"displayName"`
+	Email       string `json:"email"`
+	Password    string `json:"password"`
+	Role        string `json:"role"`
+	DivisionID  int    `json:"divisionId"`
+}
+
+// ImportUserRequest backs the admin-only import route, which keeps the source
+// system's createdAt instead of the insert time.
+type ImportUserRequest struct {
+	CreateUserRequest
+	CreatedAt *time.Time `json:"createdAt"`
 }
 
 type UpdateUserRequest struct {
-	Name       string  `json:"name"`
-	Phone      *string `json:"phone"`
-	Role       string  `json:"role"`
-	DivisionID int     `json:"divisionId"`
-	IsActive   *bool   `json:"isActive"`
+	Username    string                 `json:"username"`
+	DisplayName string                 `json:"displayName"`
+	Phone       nullable.Field[string] `json:"phone"`
+	AvatarURL   nullable.Field[string] `json:"avatarUrl"`
+	Role        string                 `json:"role"`
+	DivisionID  int                    `json:"divisionId"`
+	IsActive    *bool                  `json:"isActive"`
+}
+
+type UpdateAvailabilityRequest struct {
+	IsAvailable *bool `json:"isAvailable"`
+}
+
+type CheckEmailsRequest struct {
+	Emails []string `json:"emails"`
+}
+
+type CheckEmailsResponse struct {
+	Taken     []string `json:"taken"`
+	Available []string `json:"available"`
+}
+
+type ResetPasswordResponse struct {
+	ID       int    `json:"id"`
+	Password string `json:"password"`
+}
+
+type CheckUsersExistRequest struct {
+	IDs    []int    `json:"ids"`
+	Emails []string `json:"emails"`
+}
+
+type CheckUsersExistResponse struct {
+	IDs    map[int]response.ExistenceResult    `json:"ids"`
+	Emails map[string]response.ExistenceResult `json:"emails"`
 }
 
 type UserResponse struct {
-	ID        int       `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	AvatarURL *string   `json:"avatarUrl"`
-	Phone     *string   `json:"phone"`
-	Role      string    `json:"role"`
-	Division  Division  `json:"division"`
-	IsActive  bool      `json:"isActive"`
-	CreatedAt time.Time `json:"createdAt"`
+	ID          int                `json:"id"`
+	Username    string             `json:"username"`
+	DisplayName string             `json:"displayName"`
+	Email       string             `json:"email"`
+	AvatarURL   *string            `json:"avatarUrl"`
+	Initials    string             `json:"initials,omitempty"`
+	AvatarColor string             `json:"avatarColor,omitempty"`
+	Phone       *string            `json:"phone"`
+	Role        string             `json:"role"`
+	Division    Division           `json:"division"`
+	IsActive    bool               `json:"isActive"`
+	IsAvailable bool               `json:"isAvailable"`
+	Images      map[string]*string `json:"images"`
+	CreatedAt   time.Time          `json:"createdAt"`
+
+	// GeneratedPassword is only set on the POST /users/provision response;
+	// it is never returned again.
+	GeneratedPassword string `json:"generatedPassword,omitempty"`
+}
+
+type TicketStatsResponse struct {
+	Total                  int            `json:"total"`
+	ByStatus               map[string]int `json:"byStatus"`
+	AverageResolutionHours *float64       `json:"averageResolutionHours"`
+}
+
+type UserTicketStatsResponse struct {
+	UserID   int                  `json:"userId"`
+	Created  TicketStatsResponse  `json:"created"`
+	Assigned *TicketStatsResponse `json:"assigned,omitempty"`
 }
 
 type Division struct {
@@ -40,45 +120,107 @@ type Division struct {
 	Name string `json:"name"`
 }
 
+// DivisionHistoryResponse has a null from for a user's first division and a
+// null from or to once that division has been deleted.
+type DivisionHistoryResponse struct {
+	ID        int       `json:"id"`
+	From      *Division `json:"from"`
+	To        *Division `json:"to"`
+	ChangedAt time.Time `json:"changedAt"`
+}
+
+type GetDivisionHistoryQuery struct {
+	response.PaginationQuery
+}
+
+type DeleteUserQuery struct {
+	ReassignCreatedTo int `query:"reassignCreatedTo"`
+}
+
 type GetUsersQuery struct {
 	response.PaginationQuery
-	Name       string `query:"name"`
-	Role       string `query:"role"`
-	DivisionID int    `query:"divisionId"`
-	IsActive   *bool  `query:"isActive"`
+	Name         string `query:"name"`
+	Role         string `query:"role"`
+	DivisionID   int    `query:"divisionId"`
+	IsActive     *bool  `query:"isActive"`
+	CreatedAt    string `query:"createdAt"`
+	CreatedMonth string `query:"createdMonth"`
+
+	DivisionUnchangedForDays int    `query:"divisionUnchangedForDays"`
+	Sort                     string `query:"sort"`
 }
 
 type UserListFilter struct {
-	Page       int
-	Limit      int
-	Offset     int
-	Name       string
-	Role       string
-	DivisionID int
-	IsActive   *bool
+	response.Pagination
+	Name         string
+	Role         string
+	DivisionID   int
+	IsActive     *bool
+	CreatedAt    *response.DateRange
+	CreatedMonth *response.DateRange
+	Sort         *query.Sort
+
+	DivisionUnchangedForDays int
 }
 
-func (r *CreateUserRequest) Validate() error {
+func (r *CreateUserRequest) Validate(credentials CredentialPolicy) error {
 	v := validator.New()
 
-	validator.ValidateString(v, "name", r.Name, true, 2, 50)
+	r.validate(v, credentials)
+
+	if !v.Valid() {
+		return v.ToAppError()
+	}
+
+	return nil
+}
+
+func (r *ImportUserRequest) Validate(credentials CredentialPolicy, now time.Time) error {
+	v := validator.New()
+
+	r.validate(v, credentials)
+	validator.ValidateBackdate(v, "createdAt", r.CreatedAt, now)
+
+	if !v.Valid() {
+		return v.ToAppError()
+	}
+
+	return nil
+}
+
+func (r *CreateUserRequest) validate(v *validator.Validator, credentials CredentialPolicy) {
+	validator.ValidateUsername(v, "username", strings.TrimSpace(r.Username))
+	validator.ValidateString(v, "displayName", strings.TrimSpace(r.DisplayName), true, 2, 50)
 	validator.ValidateString(v, "email", r.Email, true, 5, 255)
 	if r.Email != "" && !validator.ValidateEmail(r.Email) {
 		v.AddError("email", "Must be a valid email address")
 	}
-	validator.ValidateString(v, "password", r.Password, true, 6, 255)
+	validator.ValidateNotDisposableEmail(v, "email", r.Email, credentials.DisposableEmailDomains)
+	validator.ValidatePassword(v, "password", r.Password, credentials.RejectCommonPasswords)
+
+	validator.ValidateEnum(v, "role", strings.TrimSpace(r.Role), ValidRoles, true)
 
-	role := strings.TrimSpace(r.Role)
-	if role == "" {
-		v.AddError("role", "Required")
-	} else if !ValidRoles[role] {
-		v.AddError("role", "Must be one of: ADMIN, IT, STAFF")
+	if r.DivisionID <= 0 {
+		v.AddError("divisionId", "Required and must be greater than 0")
 	}
+}
+
+func (r *UpdateUserRequest) Validate() error {
+	v := validator.New()
+
+	validator.ValidateUsername(v, "username", strings.TrimSpace(r.Username))
+	validator.ValidateString(v, "displayName", strings.TrimSpace(r.DisplayName), true, 2, 50)
+
+	validator.ValidateEnum(v, "role", strings.TrimSpace(r.Role), ValidRoles, true)
 
 	if r.DivisionID <= 0 {
 		v.AddError("divisionId", "Required and must be greater than 0")
 	}
 
+	if r.AvatarURL.Valid {
+		v.AddError("avatarUrl", "avatarUrl can only be set to null; upload images with PATCH /users/:id/avatar")
+	}
+
 	if !v.Valid() {
 		return v.ToAppError()
 	}
@@ -86,20 +228,25 @@ func (r *CreateUserRequest) Validate() error {
 	return nil
 }
 
-func (r *UpdateUserRequest) Validate() error {
+func (r *UpdateAvailabilityRequest) Validate() error {
 	v := validator.New()
 
-	validator.ValidateString(v, "name", r.Name, true, 2, 50)
+	v.Check(r.IsAvailable != nil, "isAvailable", "isAvailable is required")
 
-	role := strings.TrimSpace(r.Role)
-	if role == "" {
-		v.AddError("role", "Required")
-	} else if !ValidRoles[role] {
-		v.AddError("role", "Must be one of: ADMIN, IT, STAFF")
+	if !v.Valid() {
+		return v.ToAppError()
 	}
 
-	if r.DivisionID <= 0 {
-		v.AddError("divisionId", "Required and must be greater than 0")
+	return nil
+}
+
+func (r *CheckEmailsRequest) Validate() error {
+	v := validator.New()
+
+	if len(r.Emails) == 0 {
+		v.AddError("emails", "emails must contain at least one email")
+	} else if len(r.Emails) > MaxCheckEmails {
+		v.AddError("emails", fmt.Sprintf("emails must not contain more than %d emails", MaxCheckEmails))
 	}
 
 	if !v.Valid() {
@@ -109,36 +256,98 @@ func (r *UpdateUserRequest) Validate() error {
 	return nil
 }
 
-func (q *GetUsersQuery) Normalize() (*UserListFilter, error) {
-	page, limit, offset := q.NormalizePagination()
+func (r *CheckUsersExistRequest) Validate() error {
+	v := validator.New()
+
+	total := len(r.IDs) + len(r.Emails)
+	if total == 0 {
+		v.AddError("ids", "ids or emails must contain at least one value")
+	} else if total > MaxExistsLookups {
+		v.AddError("ids", fmt.Sprintf("ids and emails together must not contain more than %d values", MaxExistsLookups))
+	}
+
+	if len(r.IDs) > 0 && total <= MaxExistsLookups {
+		validator.ValidateIDs(v, "ids", r.IDs, MaxExistsLookups)
+	}
+
+	if !v.Valid() {
+		return v.ToAppError()
+	}
+
+	return nil
+}
+
+func (q *GetUsersQuery) Normalize(opts response.ListOptions) (*UserListFilter, error) {
+	pagination, err := q.NormalizePagination(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	v := validator.New()
+	validator.ValidateFilterID(v, "divisionId", q.DivisionID)
+	v.Check(q.DivisionUnchangedForDays >= 0 && q.DivisionUnchangedForDays <= MaxDivisionUnchangedDays, "divisionUnchangedForDays", fmt.Sprintf("divisionUnchangedForDays must be between 0 and %d", MaxDivisionUnchangedDays))
+	if !v.Valid() {
+		return nil, v.ToAppError()
+	}
+
+	now := opts.Now()
+
+	createdAt, err := response.ParseDateFilter(q.CreatedAt, now)
+	if err != nil {
+		return nil, err
+	}
+
+	createdMonth, err := response.ParseMonthFilter("createdMonth", q.CreatedMonth, now)
+	if err != nil {
+		return nil, err
+	}
+
+	sort, err := query.ParseSort(q.Sort, "username", "displayName", "email", "createdAt")
+	if err != nil {
+		return nil, err
+	}
 
 	return &UserListFilter{
-		Page:       page,
-		Limit:      limit,
-		Offset:     offset,
-		Name:       strings.TrimSpace(q.Name),
-		Role:       strings.TrimSpace(q.Role),
-		DivisionID: q.DivisionID,
-		IsActive:   q.IsActive,
+		Pagination:   pagination,
+		Name:         strings.TrimSpace(q.Name),
+		Role:         strings.TrimSpace(q.Role),
+		DivisionID:   q.DivisionID,
+		IsActive:     q.IsActive,
+		CreatedAt:    createdAt,
+		CreatedMonth: createdMonth,
+		Sort:         sort,
+
+		DivisionUnchangedForDays: q.DivisionUnchangedForDays,
 	}, nil
 }
 
 func ToUserResponse(u *UserWithDivision, baseURL string) *UserResponse {
 	avatarURL := buildFullURL(u.AvatarURL, baseURL)
 
+	var initials, avatarColor string
+	if avatarURL == nil {
+		initials = buildInitials(u.DisplayName, u.Username)
+		avatarColor = buildAvatarColor(u.ID)
+	}
+
 	return &UserResponse{
-		ID:        u.ID,
-		Name:      u.Name,
-		Email:     u.Email,
-		AvatarURL: avatarURL,
-		Phone:     u.Phone,
-		Role:      u.Role,
+		ID:          u.ID,
+		Username:    u.Username,
+		DisplayName: u.DisplayName,
+		Email:       u.Email,
+		AvatarURL:   avatarURL,
+		Initials:    initials,
+		AvatarColor: avatarColor,
+		Phone:       u.Phone,
+		Role:        u.Role,
 		Division: Division{
 			ID:   u.DivisionID,
 			Name: u.DivisionName,
 		},
-		IsActive:  u.IsActive,
-		CreatedAt: u.CreatedAt,
+		IsActive:    u.IsActive,
+		IsAvailable: u.IsAvailable,
+		Images:      buildImageURLs(u, baseURL),
+		CreatedAt:   u.CreatedAt,
 	}
 }
 
@@ -150,6 +359,65 @@ func ToUserResponses(users []UserWithDivision, baseURL string) []UserResponse {
 	return results
 }
 
+func ToTicketStatsResponse(stats *TicketStats) *TicketStatsResponse {
+	result := &TicketStatsResponse{
+		ByStatus: make(map[string]int, len(stats.StatusCounts)),
+	}
+
+	for _, sc := range stats.StatusCounts {
+		result.ByStatus[sc.Status] = sc.Count
+		result.Total += sc.Count
+	}
+
+	if stats.AvgResolutionSeconds != nil {
+		hours := math.Round(*stats.AvgResolutionSeconds/3600*100) / 100
+		result.AverageResolutionHours = &hours
+	}
+
+	return result
+}
+
+func ToDivisionHistoryResponse(h *DivisionHistory) *DivisionHistoryResponse {
+	return &DivisionHistoryResponse{
+		ID:        h.ID,
+		From:      toHistoryDivision(h.FromDivisionID, h.FromDivisionName),
+		To:        toHistoryDivision(h.ToDivisionID, h.ToDivisionName),
+		ChangedAt: h.ChangedAt,
+	}
+}
+
+func ToDivisionHistoryResponses(history []DivisionHistory) []DivisionHistoryResponse {
+	return response.MapResponses(history, ToDivisionHistoryResponse)
+}
+
+func toHistoryDivision(id *int, name *string) *Division {
+	if id == nil {
+		return nil
+	}
+	division := &Division{ID: *id}
+	if name != nil {
+		division.Name = *name
+	}
+	return division
+}
+
+func buildImageURLs(u *UserWithDivision, baseURL string) map[string]*string {
+	images := make(map[string]*string, len(ValidImageSlots))
+	for _, slot := range ValidImageSlots {
+		if slot == ImageSlotAvatar {
+			images[slot] = buildFullURL(u.AvatarURL, baseURL)
+			continue
+		}
+
+		var path *string
+		if url, ok := u.Images[slot]; ok {
+			path = &url
+		}
+		images[slot] = buildFullURL(path, baseURL)
+	}
+	return images
+}
+
 func buildFullURL(relativePath *string, baseURL string) *string {
 	if relativePath == nil || *relativePath == "" {
 		return nil
@@ -157,3 +425,37 @@ func buildFullURL(relativePath *string, baseURL string) *string {
 	fullURL := baseURL + *relativePath
 	return &fullURL
 }
+
+// buildInitials takes the first letter of the first and last words, or just
+// the first letter for a single word, falling back to the username.
+func buildInitials(displayName, username string) string {
+	var letters []rune
+	for _, word := range strings.Fields(displayName) {
+		for _, r := range word {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				letters = append(letters, unicode.ToUpper(r))
+				break
+			}
+		}
+	}
+
+	switch len(letters) {
+	case 0:
+		for _, r := range username {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return string(unicode.ToUpper(r))
+			}
+		}
+		return ""
+	case 1:
+		return string(letters[0])
+	default:
+		return string([]rune{letters[0], letters[len(letters)-1]})
+	}
+}
+
+func buildAvatarColor(id int) string {
+	h := fnv.New32a()
+	h.Write([]byte(strconv.Itoa(id)))
+	return avatarColors[h.Sum32()%uint32(len(avatarColors))]
+}
@@ -11,12 +11,14 @@ import (
 )
 
 type Handler struct {
-	service Service
+	service     Service
+	imageLimits uploads.ImageLimits
 }
 
-func NewHandler(service Service) *Handler {
+func NewHandler(service Service, imageLimits uploads.ImageLimits) *Handler {
 	return &Handler{
-		service: service,
+		service:     service,
+		imageLimits: imageLimits,
 	}
 }
 
@@ -49,6 +51,110 @@ func (h *Handler) GetByID(c *echo.Context) error {
 	return response.OK(c, "User retrieved successfully", user)
 }
 
+func (h *Handler) GetDistinctValues(c *echo.Context) error {
+	var req response.DistinctQuery
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Invalid query parameters"))
+	}
+
+	values, err := h.service.GetDistinctValues(c.Request().Context(), &req)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	response.SetCacheControl(c, response.DistinctMaxAge)
+	return response.OK(c, "Distinct values retrieved successfully", values)
+}
+
+func (h *Handler) GetTicketStats(c *echo.Context) error {
+	idParam := c.Param("id")
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		return response.Error(c, errors.BadRequest("Invalid user ID"))
+	}
+
+	stats, err := h.service.GetTicketStats(c.Request().Context(), id)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.OK(c, "User ticket stats retrieved successfully", stats)
+}
+
+func (h *Handler) GetDivisionHistory(c *echo.Context) error {
+	idParam := c.Param("id")
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		return response.Error(c, errors.BadRequest("Invalid user ID"))
+	}
+
+	var req GetDivisionHistoryQuery
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Invalid query parameters"))
+	}
+
+	history, err := h.service.GetDivisionHistory(c.Request().Context(), id, &req)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.OK(c, "Division history retrieved successfully", history)
+}
+
+func (h *Handler) GetAvatar(c *echo.Context) error {
+	idParam := c.Param("id")
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		return response.Error(c, errors.BadRequest("Invalid user ID"))
+	}
+
+	avatarPath, err := h.service.GetAvatarPath(c.Request().Context(), id)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	file, info, err := uploads.OpenFile(avatarPath)
+	if err != nil {
+		return response.Error(c, err)
+	}
+	defer file.Close()
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentType, uploads.ContentType(avatarPath))
+	header.Set("Cache-Control", "private, max-age=86400")
+
+	http.ServeContent(c.Response(), c.Request(), info.Name(), info.ModTime(), file)
+	return nil
+}
+
+func (h *Handler) CheckEmails(c *echo.Context) error {
+	var req CheckEmailsRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, err)
+	}
+
+	result, err := h.service.CheckEmails(c.Request().Context(), &req)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.OK(c, "Emails checked successfully", result)
+}
+
+func (h *Handler) CheckExist(c *echo.Context) error {
+	var req CheckUsersExistRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, err)
+	}
+
+	result, err := h.service.CheckExist(c.Request().Context(), &req)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.OK(c, "Users checked successfully", result)
+}
+
 func (h *Handler) Create(c *echo.Context) error {
 	var req CreateUserRequest
 
@@ -61,9 +167,42 @@ func (h *Handler) Create(c *echo.Context) error {
 		return response.Error(c, err)
 	}
 
+	response.SetLocation(c, "users", user.ID)
 	return response.Created(c, "User created successfully", user)
 }
 
+func (h *Handler) CreateWithGeneratedPassword(c *echo.Context) error {
+	var req CreateUserRequest
+
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, err)
+	}
+
+	user, err := h.service.CreateWithGeneratedPassword(c.Request().Context(), &req)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	response.SetLocation(c, "users", user.ID)
+	return response.Created(c, "User created successfully", user)
+}
+
+func (h *Handler) Import(c *echo.Context) error {
+	var req ImportUserRequest
+
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, err)
+	}
+
+	user, err := h.service.Import(c.Request().Context(), &req)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	response.SetLocation(c, "users", user.ID)
+	return response.Created(c, "User imported successfully", user)
+}
+
 func (h *Handler) Update(c *echo.Context) error {
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
@@ -72,7 +211,7 @@ func (h *Handler) Update(c *echo.Context) error {
 	}
 
 	var req UpdateUserRequest
-	if err := c.Bind(&req); err != nil {
+	if err := response.BindPatch(c, &req, immutableUserFields); err != nil {
 		return response.Error(c, err)
 	}
 
@@ -96,7 +235,7 @@ func (h *Handler) UpdateAvatar(c *echo.Context) error {
 		return response.Error(c, errors.BadRequest("Avatar file is required"))
 	}
 
-	avatarURL, err := uploads.SaveAvatarImage(fileHeader)
+	avatarURL, err := uploads.SaveAvatarImage(fileHeader, h.imageLimits)
 	if err != nil {
 		return response.Error(c, err)
 	}
@@ -110,6 +249,67 @@ func (h *Handler) UpdateAvatar(c *echo.Context) error {
 	return response.OK(c, "Avatar updated successfully", user)
 }
 
+func (h *Handler) UpdateAvailability(c *echo.Context) error {
+	idParam := c.Param("id")
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		return response.Error(c, errors.BadRequest("Invalid user ID"))
+	}
+
+	var req UpdateAvailabilityRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, err)
+	}
+
+	user, err := h.service.UpdateAvailability(c.Request().Context(), id, &req)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.OK(c, "Availability updated successfully", user)
+}
+
+func (h *Handler) ResetPassword(c *echo.Context) error {
+	idParam := c.Param("id")
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		return response.Error(c, errors.BadRequest("Invalid user ID"))
+	}
+
+	result, err := h.service.ResetPassword(c.Request().Context(), id)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.OK(c, "Password reset successfully", result)
+}
+
+func (h *Handler) UpdateImage(c *echo.Context) error {
+	idParam := c.Param("id")
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		return response.Error(c, errors.BadRequest("Invalid user ID"))
+	}
+
+	fileHeader, err := c.FormFile("image")
+	if err != nil {
+		return response.Error(c, errors.BadRequest("Image file is required"))
+	}
+
+	imageURL, err := uploads.SaveAvatarImage(fileHeader, h.imageLimits)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	user, err := h.service.UpdateImage(c.Request().Context(), id, c.Param("slot"), imageURL)
+	if err != nil {
+		uploads.DeleteFile(imageURL)
+		return response.Error(c, err)
+	}
+
+	return response.OK(c, "Image updated successfully", user)
+}
+
 func (h *Handler) Delete(c *echo.Context) error {
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
@@ -117,7 +317,12 @@ func (h *Handler) Delete(c *echo.Context) error {
 		return response.Error(c, errors.BadRequest("Invalid user ID"))
 	}
 
-	if err := h.service.Delete(c.Request().Context(), id); err != nil {
+	var req DeleteUserQuery
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Invalid query parameters"))
+	}
+
+	if err := h.service.Delete(c.Request().Context(), id, &req); err != nil {
 		return response.Error(c, err)
 	}
 
@@ -126,3 +331,18 @@ func (h *Handler) Delete(c *echo.Context) error {
 		"message": "User deleted successfully",
 	})
 }
+
+func (h *Handler) DeleteImage(c *echo.Context) error {
+	idParam := c.Param("id")
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		return response.Error(c, errors.BadRequest("Invalid user ID"))
+	}
+
+	user, err := h.service.DeleteImage(c.Request().Context(), id, c.Param("slot"))
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.OK(c, "Image deleted successfully", user)
+}
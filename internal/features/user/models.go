@@ -8,35 +8,71 @@ const (
 	RoleStaff = "STAFF"
 )
 
-var ValidRoles = map[string]bool{
-	RoleAdmin: true,
-	RoleIT:    true,
-	RoleStaff: true,
-}
+var ValidRoles = []string{RoleAdmin, RoleIT, RoleStaff}
+
+const (
+	ImageSlotAvatar = "avatar"
+	ImageSlotCover  = "cover"
+)
+
+var ValidImageSlots = []string{ImageSlotAvatar, ImageSlotCover}
+
+var DistinctFields = []string{"role", "divisionId", "isActive", "isAvailable"}
 
 type User struct {
-	ID         int       `db:"id" json:"id"`
-	Name       string    `db:"name" json:"name"`
-	Email      string    `db:"email" json:"email"`
-	Password   string    `db:"password" json:"-"`
-	AvatarURL  *string   `db:"avatar_url" json:"avatarUrl"`
-	Phone      *string   `db:"phone" json:"phone"`
-	Role       string    `db:"role" json:"role"`
-	DivisionID int       `db:"division_id" json:"divisionId"`
-	IsActive   bool      `db:"is_active" json:"isActive"`
-	CreatedAt  time.Time `db:"created_at" json:"createdAt"`
+	ID          int       `db:"id" json:"id"`
+	Username    string    `db:"username" json:"username"`
+	DisplayName string    `db:"display_name" json:"displayName"`
+	Email       string    `db:"email" json:"email"`
+	Password    string    `db:"password" json:"-"`
+	AvatarURL   *string   `db:"avatar_url" json:"avatarUrl"`
+	Phone       *string   `db:"phone" json:"phone"`
+	Role        string    `db:"role" json:"role"`
+	DivisionID  int       `db:"division_id" json:"divisionId"`
+	IsActive    bool      `db:"is_active" json:"isActive"`
+	IsAvailable bool      `db:"is_available" json:"isAvailable"`
+	CreatedAt   time.Time `db:"created_at" json:"createdAt"`
 }
 
 type UserWithDivision struct {
-	ID           int       `db:"id" json:"id"`
-	Name         string    `db:"name" json:"name"`
-	Email        string    `db:"email" json:"email"`
-	Password     string    `db:"password" json:"-"`
-	AvatarURL    *string   `db:"avatar_url" json:"avatarUrl"`
-	Phone        *string   `db:"phone" json:"phone"`
-	Role         string    `db:"role" json:"role"`
-	DivisionID   int       `db:"division_id" json:"divisionId"`
-	DivisionName string    `db:"division_name" json:"divisionName"`
-	IsActive     bool      `db:"is_active" json:"isActive"`
-	CreatedAt    time.Time `db:"created_at" json:"createdAt"`
+	ID           int               `db:"id" json:"id"`
+	Username     string            `db:"username" json:"username"`
+	DisplayName  string            `db:"display_name" json:"displayName"`
+	Email        string            `db:"email" json:"email"`
+	Password     string            `db:"password" json:"-"`
+	AvatarURL    *string           `db:"avatar_url" json:"avatarUrl"`
+	Phone        *string           `db:"phone" json:"phone"`
+	Role         string            `db:"role" json:"role"`
+	DivisionID   int               `db:"division_id" json:"divisionId"`
+	DivisionName string            `db:"division_name" json:"divisionName"`
+	IsActive     bool              `db:"is_active" json:"isActive"`
+	IsAvailable  bool              `db:"is_available" json:"isAvailable"`
+	CreatedAt    time.Time         `db:"created_at" json:"createdAt"`
+	Images       map[string]string `db:"-" json:"-"`
+}
+
+type UserImage struct {
+	UserID   int    `db:"user_id"`
+	Slot     string `db:"slot"`
+	ImageURL string `db:"image_url"`
+}
+
+type TicketStatusCount struct {
+	Status string `db:"status"`
+	Count  int    `db:"count"`
+}
+
+type TicketStats struct {
+	StatusCounts         []TicketStatusCount
+	AvgResolutionSeconds *float64
+}
+
+type DivisionHistory struct {
+	ID               int       `db:"id" json:"id"`
+	UserID           int       `db:"user_id" json:"userId"`
+	FromDivisionID   *int      `db:"from_division_id" json:"fromDivisionId"`
+	FromDivisionName *string   `db:"from_division_name" json:"fromDivisionName"`
+	ToDivisionID     *int      `db:"to_division_id" json:"toDivisionId"`
+	ToDivisionName   *string   `db:"to_division_name" json:"toDivisionName"`
+	ChangedAt        time.Time `db:"changed_at" json:"changedAt"`
 }
@@ -6,54 +6,116 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
+
+	"helpdesk/internal/utils/query"
+	"helpdesk/internal/utils/response"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 )
 
+var userSortColumns = map[string]string{
+	"username":    "u.username",
+	"displayName": "u.display_name",
+	"email":       "u.email",
+	"createdAt":   "u.created_at",
+}
+
+var userDistinctColumns = map[string]string{
+	"role":        "role",
+	"divisionId":  "division_id",
+	"isActive":    "is_active",
+	"isAvailable": "is_available",
+}
+
+// OpenAssignmentsError reports tickets still assigned to a user whose role
+// change would leave them without an IT assignee.
+type OpenAssignmentsError struct {
+	TicketIDs []int
+}
+
+func (e *OpenAssignmentsError) Error() string {
+	return fmt.Sprintf("user has %d open assigned tickets", len(e.TicketIDs))
+}
+
+// CreatedTicketsError reports tickets created by a user that is being deleted
+// without a user to reassign them to. Count is 0 when it could not be
+// determined.
+type CreatedTicketsError struct {
+	Count int
+}
+
+func (e *CreatedTicketsError) Error() string {
+	return fmt.Sprintf("user created %d tickets", e.Count)
+}
+
 type Repository interface {
 	GetAll(ctx context.Context, filter *UserListFilter) ([]UserWithDivision, int, error)
 	GetByID(ctx context.Context, id int) (*UserWithDivision, error)
 	GetByEmail(ctx context.Context, email string) (*User, error)
-	GetByName(ctx context.Context, name string) (*User, error)
+	GetByUsername(ctx context.Context, username string) (*User, error)
+	GetByIDs(ctx context.Context, ids []int) ([]User, error)
+	GetByEmails(ctx context.Context, emails []string) ([]User, error)
+	GetExistingEmails(ctx context.Context, emails []string) ([]string, error)
 	Exists(ctx context.Context, id int) (bool, error)
-	Create(ctx context.Context, name, email, passwordHash string, avatarURL, phone, role string, divisionID int) (*UserWithDivision, error)
-	Update(ctx context.Context, id int, name, phone, role string, divisionID int, isActive bool) (*UserWithDivision, error)
+	CountUploads(ctx context.Context, id int) (int, error)
+	GetDistinctValues(ctx context.Context, field string) ([]any, error)
+	GetCreatedTicketStats(ctx context.Context, id int) (*TicketStats, error)
+	GetAssignedTicketStats(ctx context.Context, id int) (*TicketStats, error)
+	GetImage(ctx context.Context, userID int, slot string) (*UserImage, error)
+	GetDivisionHistory(ctx context.Context, userID int, pagination response.Pagination) ([]DivisionHistory, int, error)
+	Create(ctx context.Context, username, displayName, email, passwordHash string, avatarURL, phone, role string, divisionID int, createdAt *time.Time) (*UserWithDivision, error)
+	Update(ctx context.Context, id int, username, displayName string, phone *string, role string, divisionID int, isActive, clearAvatar bool) (*UserWithDivision, error)
 	UpdateAvatar(ctx context.Context, id int, avatarURL string) (*UserWithDivision, error)
-	Delete(ctx context.Context, id int) error
+	UpdateAvailability(ctx context.Context, id int, isAvailable bool) (*UserWithDivision, error)
+	UpdatePassword(ctx context.Context, id int, passwordHash string) (bool, error)
+	UpsertImage(ctx context.Context, userID int, slot, imageURL string) error
+	Delete(ctx context.Context, id, reassignCreatedTo int) error
+	DeleteImage(ctx context.Context, userID int, slot string) error
 }
 
+// readDB serves list and stats queries that tolerate replica lag; it is the
+// same handle as db when no replica is configured.
 type repository struct {
-	db *sqlx.DB
+	db       *sqlx.DB
+	readDB   *sqlx.DB
+	timeouts query.Timeouts
 }
 
-func NewRepository(db *sqlx.DB) Repository {
-	return &repository{db: db}
+func NewRepository(db, readDB *sqlx.DB, timeouts query.Timeouts) Repository {
+	return &repository{db: db, readDB: readDB, timeouts: timeouts}
 }
 
 func (r *repository) GetAll(ctx context.Context, filter *UserListFilter) ([]UserWithDivision, int, error) {
+	ctx, cancel := r.timeouts.WithTimeout(ctx, query.OpList)
+	defer cancel()
+
 	whereClause, args := buildUserFilterWhereClause(filter)
 
-	countQuery := `SELECT COUNT(*) FROM users` + whereClause
+	countQuery := `SELECT COUNT(*) FROM users u` + whereClause
 	var totalItems int
-	if err := r.db.GetContext(ctx, &totalItems, countQuery, args...); err != nil {
-		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+	if filter.WithTotal {
+		if err := r.readDB.GetContext(ctx, &totalItems, countQuery, args...); err != nil {
+			return nil, 0, fmt.Errorf("failed to count users: %w", err)
+		}
 	}
 
 	limitPlaceholder := len(args) + 1
 	offsetPlaceholder := len(args) + 2
-	query := fmt.Sprintf(`
-		SELECT u.id, u.name, u.email, u.password, u.avatar_url, u.phone, u.role, u.division_id, d.name as division_name, u.is_active, u.created_at 
+	orderBy := query.OrderBy(filter.Sort, userSortColumns, "u.created_at DESC, u.id DESC", "u.id")
+	listQuery := fmt.Sprintf(`
+		SELECT u.id, u.username, u.display_name, u.email, u.password, u.avatar_url, u.phone, u.role, u.division_id, d.name as division_name, u.is_active, u.is_available, u.created_at 
 		FROM users u 
 		INNER JOIN divisions d ON u.division_id = d.id
 		%s 
-		ORDER BY u.created_at DESC, u.id DESC 
+		%s 
 		LIMIT $%d OFFSET $%d
-	`, whereClause, limitPlaceholder, offsetPlaceholder)
+	`, whereClause, orderBy, limitPlaceholder, offsetPlaceholder)
 	listArgs := append(args, filter.Limit, filter.Offset)
 
 	var users []UserWithDivision
-	err := r.db.SelectContext(ctx, &users, query, listArgs...)
+	err := r.readDB.SelectContext(ctx, &users, listQuery, listArgs...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get users: %w", err)
 	}
@@ -62,12 +124,16 @@ func (r *repository) GetAll(ctx context.Context, filter *UserListFilter) ([]User
 		users = []UserWithDivision{}
 	}
 
+	if err := r.attachImages(ctx, r.readDB, users); err != nil {
+		return nil, 0, err
+	}
+
 	return users, totalItems, nil
 }
 
 func (r *repository) GetByID(ctx context.Context, id int) (*UserWithDivision, error) {
 	query := `
-		SELECT u.id, u.name, u.email, u.password, u.avatar_url, u.phone, u.role, u.division_id, d.name as division_name, u.is_active, u.created_at 
+		SELECT u.id, u.username, u.display_name, u.email, u.password, u.avatar_url, u.phone, u.role, u.division_id, d.name as division_name, u.is_active, u.is_available, u.created_at 
 		FROM users u 
 		INNER JOIN divisions d ON u.division_id = d.id 
 		WHERE u.id = $1
@@ -82,11 +148,16 @@ func (r *repository) GetByID(ctx context.Context, id int) (*UserWithDivision, er
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	return &user, nil
+	users := []UserWithDivision{user}
+	if err := r.attachImages(ctx, r.db, users); err != nil {
+		return nil, err
+	}
+
+	return &users[0], nil
 }
 
 func (r *repository) GetByEmail(ctx context.Context, email string) (*User, error) {
-	query := `SELECT id, name, email, password, avatar_url, phone, role, division_id, is_active, created_at FROM users WHERE LOWER(email) = LOWER($1)`
+	query := `SELECT id, username, display_name, email, password, avatar_url, phone, role, division_id, is_active, is_available, created_at FROM users WHERE LOWER(email) = LOWER($1)`
 
 	var user User
 	err := r.db.GetContext(ctx, &user, query, email)
@@ -100,11 +171,11 @@ func (r *repository) GetByEmail(ctx context.Context, email string) (*User, error
 	return &user, nil
 }
 
-func (r *repository) GetByName(ctx context.Context, name string) (*User, error) {
-	query := `SELECT id, name, email, password, avatar_url, phone, role, division_id, is_active, created_at FROM users WHERE name = $1`
+func (r *repository) GetByUsername(ctx context.Context, username string) (*User, error) {
+	query := `SELECT id, username, display_name, email, password, avatar_url, phone, role, division_id, is_active, is_available, created_at FROM users WHERE LOWER(username) = LOWER($1)`
 
 	var user User
-	err := r.db.GetContext(ctx, &user, query, name)
+	err := r.db.GetContext(ctx, &user, query, username)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -115,6 +186,39 @@ func (r *repository) GetByName(ctx context.Context, name string) (*User, error)
 	return &user, nil
 }
 
+func (r *repository) GetByIDs(ctx context.Context, ids []int) ([]User, error) {
+	query := `SELECT id, username, display_name, email, password, avatar_url, phone, role, division_id, is_active, is_available, created_at FROM users WHERE id = ANY($1) ORDER BY id`
+
+	var users []User
+	if err := r.db.SelectContext(ctx, &users, query, pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+
+	return users, nil
+}
+
+func (r *repository) GetByEmails(ctx context.Context, emails []string) ([]User, error) {
+	query := `SELECT id, username, display_name, email, password, avatar_url, phone, role, division_id, is_active, is_available, created_at FROM users WHERE LOWER(email) = ANY($1) ORDER BY id`
+
+	var users []User
+	if err := r.db.SelectContext(ctx, &users, query, pq.Array(emails)); err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+
+	return users, nil
+}
+
+func (r *repository) GetExistingEmails(ctx context.Context, emails []string) ([]string, error) {
+	query := `SELECT LOWER(email) FROM users WHERE LOWER(email) = ANY($1)`
+
+	var existing []string
+	if err := r.db.SelectContext(ctx, &existing, query, pq.Array(emails)); err != nil {
+		return nil, fmt.Errorf("failed to check existing emails: %w", err)
+	}
+
+	return existing, nil
+}
+
 func (r *repository) Exists(ctx context.Context, id int) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)`
 
@@ -127,18 +231,141 @@ func (r *repository) Exists(ctx context.Context, id int) (bool, error) {
 	return exists, nil
 }
 
-func (r *repository) Create(ctx context.Context, name, email, passwordHash string, avatarURL, phone, role string, divisionID int) (*UserWithDivision, error) {
+func (r *repository) CountUploads(ctx context.Context, id int) (int, error) {
 	query := `
-		INSERT INTO users (name, email, password, avatar_url, phone, role, division_id) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7) 
+		SELECT
+			(SELECT COUNT(*) FROM users WHERE id = $1 AND avatar_url IS NOT NULL AND avatar_url <> '')
+			+ (SELECT COUNT(*) FROM user_images WHERE user_id = $1)
+			+ (SELECT COUNT(*) FROM ticket_attachments WHERE uploaded_by = $1)
+	`
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, query, id); err != nil {
+		return 0, fmt.Errorf("failed to count user uploads: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *repository) GetDistinctValues(ctx context.Context, field string) ([]any, error) {
+	column, ok := userDistinctColumns[field]
+	if !ok {
+		return nil, fmt.Errorf("unknown distinct field: %s", field)
+	}
+
+	ctx, cancel := r.timeouts.WithTimeout(ctx, query.OpDistinct)
+	defer cancel()
+
+	values, err := query.DistinctValues(ctx, r.readDB, "users", column)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get distinct user values: %w", err)
+	}
+
+	return values, nil
+}
+
+func (r *repository) GetCreatedTicketStats(ctx context.Context, id int) (*TicketStats, error) {
+	return r.getTicketStats(ctx, "created_by", id)
+}
+
+func (r *repository) GetAssignedTicketStats(ctx context.Context, id int) (*TicketStats, error) {
+	return r.getTicketStats(ctx, "assigned_to", id)
+}
+
+func (r *repository) getTicketStats(ctx context.Context, userColumn string, id int) (*TicketStats, error) {
+	ctx, cancel := r.timeouts.WithTimeout(ctx, query.OpStats)
+	defer cancel()
+
+	countQuery := fmt.Sprintf(`SELECT status, COUNT(*) AS count FROM tickets WHERE %s = $1 GROUP BY status ORDER BY status`, userColumn)
+
+	var counts []TicketStatusCount
+	if err := r.readDB.SelectContext(ctx, &counts, countQuery, id); err != nil {
+		return nil, fmt.Errorf("failed to count tickets: %w", err)
+	}
+
+	avgQuery := fmt.Sprintf(`
+		SELECT AVG(EXTRACT(EPOCH FROM (resolved_at - created_at)))::float8 
+		FROM tickets 
+		WHERE %s = $1 AND resolved_at IS NOT NULL
+	`, userColumn)
+
+	var avgSeconds *float64
+	if err := r.readDB.GetContext(ctx, &avgSeconds, avgQuery, id); err != nil {
+		return nil, fmt.Errorf("failed to get average resolution time: %w", err)
+	}
+
+	return &TicketStats{
+		StatusCounts:         counts,
+		AvgResolutionSeconds: avgSeconds,
+	}, nil
+}
+
+func (r *repository) GetImage(ctx context.Context, userID int, slot string) (*UserImage, error) {
+	query := `SELECT user_id, slot, image_url FROM user_images WHERE user_id = $1 AND slot = $2`
+
+	var image UserImage
+	err := r.db.GetContext(ctx, &image, query, userID, slot)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get user image: %w", err)
+	}
+
+	return &image, nil
+}
+
+func (r *repository) GetDivisionHistory(ctx context.Context, userID int, pagination response.Pagination) ([]DivisionHistory, int, error) {
+	ctx, cancel := r.timeouts.WithTimeout(ctx, query.OpList)
+	defer cancel()
+
+	var totalItems int
+	if pagination.WithTotal {
+		countQuery := `SELECT COUNT(*) FROM user_division_history WHERE user_id = $1`
+		if err := r.readDB.GetContext(ctx, &totalItems, countQuery, userID); err != nil {
+			return nil, 0, fmt.Errorf("failed to count division history: %w", err)
+		}
+	}
+
+	listQuery := `
+		SELECT h.id, h.user_id, h.from_division_id, fd.name AS from_division_name, h.to_division_id, td.name AS to_division_name, h.changed_at
+		FROM user_division_history h
+		LEFT JOIN divisions fd ON h.from_division_id = fd.id
+		LEFT JOIN divisions td ON h.to_division_id = td.id
+		WHERE h.user_id = $1
+		ORDER BY h.changed_at DESC, h.id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	var history []DivisionHistory
+	if err := r.readDB.SelectContext(ctx, &history, listQuery, userID, pagination.Limit, pagination.Offset); err != nil {
+		return nil, 0, fmt.Errorf("failed to get division history: %w", err)
+	}
+
+	if history == nil {
+		history = []DivisionHistory{}
+	}
+
+	return history, totalItems, nil
+}
+
+// Create inserts the user; a nil createdAt keeps the column default.
+func (r *repository) Create(ctx context.Context, username, displayName, email, passwordHash string, avatarURL, phone, role string, divisionID int, createdAt *time.Time) (*UserWithDivision, error) {
+	createdAtArg := query.Timestamp(createdAt)
+	query := `
+		INSERT INTO users (username, display_name, email, password, avatar_url, phone, role, division_id, created_at) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, COALESCE($9, CURRENT_TIMESTAMP)) 
 		RETURNING id
 	`
 
 	var userID int
-	err := r.db.QueryRowxContext(ctx, query, name, email, passwordHash, avatarURL, phone, role, divisionID).Scan(&userID)
+	err := r.db.QueryRowxContext(ctx, query, username, displayName, email, passwordHash, avatarURL, phone, role, divisionID, createdAtArg).Scan(&userID)
 	if err != nil {
 		var pqErr *pq.Error
 		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			if pqErr.Constraint == "idx_users_username_lower_unique" {
+				return nil, fmt.Errorf("user with username '%s' already exists", username)
+			}
 			return nil, fmt.Errorf("user with email '%s' already exists", email)
 		}
 		return nil, fmt.Errorf("failed to create user: %w", err)
@@ -147,15 +374,42 @@ func (r *repository) Create(ctx context.Context, name, email, passwordHash strin
 	return r.GetByID(ctx, userID)
 }
 
-func (r *repository) Update(ctx context.Context, id int, name, phone, role string, divisionID int, isActive bool) (*UserWithDivision, error) {
+func (r *repository) Update(ctx context.Context, id int, username, displayName string, phone *string, role string, divisionID int, isActive, clearAvatar bool) (*UserWithDivision, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var previous struct {
+		DivisionID int    `db:"division_id"`
+		Role       string `db:"role"`
+	}
+	lockQuery := `SELECT division_id, role FROM users WHERE id = $1 FOR UPDATE`
+	if err := tx.GetContext(ctx, &previous, lockQuery, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to lock user: %w", err)
+	}
+
+	if err := checkOpenAssignments(ctx, id, previous.Role, role, lockOpenAssignments(tx)); err != nil {
+		return nil, err
+	}
+
 	query := `
 		UPDATE users 
-		SET name = $1, phone = $2, role = $3, division_id = $4, is_active = $5 
-		WHERE id = $6
+		SET username = $1, display_name = $2, phone = $3, role = $4, division_id = $5, is_active = $6,
+			avatar_url = CASE WHEN $7 THEN NULL ELSE avatar_url END
+		WHERE id = $8
 	`
 
-	result, err := r.db.ExecContext(ctx, query, name, phone, role, divisionID, isActive, id)
+	result, err := tx.ExecContext(ctx, query, username, displayName, phone, role, divisionID, isActive, clearAvatar, id)
 	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, fmt.Errorf("user with username '%s' already exists", username)
+		}
 		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
 
@@ -168,11 +422,24 @@ func (r *repository) Update(ctx context.Context, id int, name, phone, role strin
 		return nil, nil
 	}
 
+	if previous.DivisionID != divisionID {
+		historyQuery := `INSERT INTO user_division_history (user_id, from_division_id, to_division_id) VALUES ($1, $2, $3)`
+		if _, err := tx.ExecContext(ctx, historyQuery, id, previous.DivisionID, divisionID); err != nil {
+			return nil, fmt.Errorf("failed to record division change: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit user update: %w", err)
+	}
+
 	return r.GetByID(ctx, id)
 }
 
+// UpdateAvatar stores NULL for an empty avatarURL so a cleared avatar is
+// returned as null rather than "".
 func (r *repository) UpdateAvatar(ctx context.Context, id int, avatarURL string) (*UserWithDivision, error) {
-	query := `UPDATE users SET avatar_url = $1 WHERE id = $2`
+	query := `UPDATE users SET avatar_url = NULLIF($1, '') WHERE id = $2`
 
 	result, err := r.db.ExecContext(ctx, query, avatarURL, id)
 	if err != nil {
@@ -191,11 +458,92 @@ func (r *repository) UpdateAvatar(ctx context.Context, id int, avatarURL string)
 	return r.GetByID(ctx, id)
 }
 
-func (r *repository) Delete(ctx context.Context, id int) error {
+func (r *repository) UpdateAvailability(ctx context.Context, id int, isAvailable bool) (*UserWithDivision, error) {
+	query := `UPDATE users SET is_available = $1 WHERE id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, isAvailable, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update availability: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return nil, nil
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+func (r *repository) UpdatePassword(ctx context.Context, id int, passwordHash string) (bool, error) {
+	query := `UPDATE users SET password = $1 WHERE id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, passwordHash, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to update password: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *repository) UpsertImage(ctx context.Context, userID int, slot, imageURL string) error {
+	query := `
+		INSERT INTO user_images (user_id, slot, image_url) 
+		VALUES ($1, $2, $3) 
+		ON CONFLICT (user_id, slot) DO UPDATE SET image_url = EXCLUDED.image_url, created_at = CURRENT_TIMESTAMP
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, userID, slot, imageURL); err != nil {
+		return fmt.Errorf("failed to save user image: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the user. Tickets they created are moved to
+// reassignCreatedTo when it is set; otherwise their existence blocks the
+// delete with a CreatedTicketsError.
+func (r *repository) Delete(ctx context.Context, id, reassignCreatedTo int) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if reassignCreatedTo > 0 {
+		reassignQuery := `UPDATE tickets SET created_by = $1 WHERE created_by = $2`
+		if _, err := tx.ExecContext(ctx, reassignQuery, reassignCreatedTo, id); err != nil {
+			return fmt.Errorf("failed to reassign created tickets: %w", err)
+		}
+	} else {
+		count, err := countCreatedTickets(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return &CreatedTicketsError{Count: count}
+		}
+	}
+
 	query := `DELETE FROM users WHERE id = $1`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := tx.ExecContext(ctx, query, id)
 	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" && pqErr.Constraint == "tickets_created_by_fkey" {
+			// A ticket was created after the check above. The failed statement
+			// aborted tx, so re-count on the pool.
+			count, _ := countCreatedTickets(ctx, r.db, id)
+			return &CreatedTicketsError{Count: count}
+		}
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
 
@@ -208,9 +556,124 @@ func (r *repository) Delete(ctx context.Context, id int) error {
 		return sql.ErrNoRows
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit user delete: %w", err)
+	}
+
+	return nil
+}
+
+func countCreatedTickets(ctx context.Context, q sqlx.QueryerContext, id int) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM tickets WHERE created_by = $1`
+	if err := sqlx.GetContext(ctx, q, &count, query, id); err != nil {
+		return 0, fmt.Errorf("failed to count created tickets: %w", err)
+	}
+	return count, nil
+}
+
+func (r *repository) DeleteImage(ctx context.Context, userID int, slot string) error {
+	query := `DELETE FROM user_images WHERE user_id = $1 AND slot = $2`
+
+	result, err := r.db.ExecContext(ctx, query, userID, slot)
+	if err != nil {
+		return fmt.Errorf("failed to delete user image: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *repository) attachImages(ctx context.Context, db *sqlx.DB, users []UserWithDivision) error {
+	if len(users) == 0 {
+		return nil
+	}
+
+	userIDs := make([]int, len(users))
+	for i, user := range users {
+		userIDs[i] = user.ID
+	}
+
+	query := `SELECT user_id, slot, image_url FROM user_images WHERE user_id = ANY($1)`
+
+	var images []UserImage
+	if err := db.SelectContext(ctx, &images, query, pq.Array(userIDs)); err != nil {
+		return fmt.Errorf("failed to get user images: %w", err)
+	}
+
+	byUser := make(map[int]map[string]string)
+	for _, image := range images {
+		if byUser[image.UserID] == nil {
+			byUser[image.UserID] = make(map[string]string)
+		}
+		byUser[image.UserID][image.Slot] = image.ImageURL
+	}
+
+	for i := range users {
+		users[i].Images = byUser[users[i].ID]
+	}
+
+	return nil
+}
+
+// openAssignmentsFunc returns the IDs of the tickets assigned to a user that
+// are not RESOLVED or CLOSED.
+type openAssignmentsFunc func(ctx context.Context, id int) ([]int, error)
+
+// checkOpenAssignments blocks moving a user into STAFF while they still have
+// open assigned tickets. It only looks the tickets up for a downgrade.
+func checkOpenAssignments(ctx context.Context, id int, previousRole, role string, openAssignments openAssignmentsFunc) error {
+	if !isDowngradeToStaff(previousRole, role) {
+		return nil
+	}
+
+	ticketIDs, err := openAssignments(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if len(ticketIDs) > 0 {
+		return &OpenAssignmentsError{TicketIDs: ticketIDs}
+	}
+
 	return nil
 }
 
+// lockOpenAssignments looks the open assigned tickets up inside tx and locks
+// them, so none can be reassigned to the user before the update commits.
+func lockOpenAssignments(tx *sqlx.Tx) openAssignmentsFunc {
+	return func(ctx context.Context, id int) ([]int, error) {
+		query := `
+			SELECT id FROM tickets
+			WHERE assigned_to = $1 AND status NOT IN ('RESOLVED', 'CLOSED')
+			ORDER BY id
+			FOR UPDATE
+		`
+
+		var ticketIDs []int
+		if err := tx.SelectContext(ctx, &ticketIDs, query, id); err != nil {
+			return nil, fmt.Errorf("failed to get open assigned tickets: %w", err)
+		}
+
+		return ticketIDs, nil
+	}
+}
+
+// isDowngradeToStaff reports whether an update moves a user into STAFF, which
+// must not keep open ticket assignments. Edits to an existing STAFF user
+// never count.
+func isDowngradeToStaff(previousRole, role string) bool {
+	return role == RoleStaff && previousRole != RoleStaff
+}
+
 func buildUserFilterWhereClause(filter *UserListFilter) (string, []interface{}) {
 	if filter == nil {
 		return "", []interface{}{}
@@ -221,7 +684,7 @@ func buildUserFilterWhereClause(filter *UserListFilter) (string, []interface{})
 
 	if filter.Name != "" {
 		args = append(args, "%"+filter.Name+"%")
-		conditions = append(conditions, fmt.Sprintf("u.name ILIKE $%d", len(args)))
+		conditions = append(conditions, fmt.Sprintf("(u.display_name ILIKE $%[1]d OR u.username ILIKE $%[1]d)", len(args)))
 	}
 
 	if filter.Role != "" {
@@ -239,6 +702,25 @@ func buildUserFilterWhereClause(filter *UserListFilter) (string, []interface{})
 		conditions = append(conditions, fmt.Sprintf("u.is_active = $%d", len(args)))
 	}
 
+	if filter.CreatedAt != nil {
+		args = append(args, filter.CreatedAt.From.UTC(), filter.CreatedAt.To.UTC())
+		conditions = append(conditions, fmt.Sprintf("u.created_at >= $%d AND u.created_at < $%d", len(args)-1, len(args)))
+	}
+
+	if filter.CreatedMonth != nil {
+		args = append(args, filter.CreatedMonth.From.UTC(), filter.CreatedMonth.To.UTC())
+		conditions = append(conditions, fmt.Sprintf("u.created_at >= $%d AND u.created_at < $%d", len(args)-1, len(args)))
+	}
+
+	// Users who never changed division count from their creation date.
+	if filter.DivisionUnchangedForDays > 0 {
+		args = append(args, filter.DivisionUnchangedForDays)
+		conditions = append(conditions, fmt.Sprintf(`COALESCE(
+			(SELECT MAX(h.changed_at) FROM user_division_history h WHERE h.user_id = u.id),
+			u.created_at
+		) < CURRENT_TIMESTAMP - make_interval(days => $%d)`, len(args)))
+	}
+
 	if len(conditions) == 0 {
 		return "", args
 	}
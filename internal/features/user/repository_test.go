@@ -0,0 +1,62 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestCheckOpenAssignments(t *testing.T) {
+	queryErr := errors.New("connection reset")
+
+	tests := []struct {
+		name         string
+		previousRole string
+		role         string
+		ticketIDs    []int
+		queryErr     error
+		wantQueried  bool
+		wantTickets  []int
+		wantErr      error
+	}{
+		{"downgrade with open tickets", RoleIT, RoleStaff, []int{10, 11}, nil, true, []int{10, 11}, nil},
+		{"downgrade without open tickets", RoleAdmin, RoleStaff, nil, nil, true, nil, nil},
+		{"lookup fails", RoleIT, RoleStaff, nil, queryErr, true, nil, queryErr},
+		{"staff edit", RoleStaff, RoleStaff, []int{10}, nil, false, nil, nil},
+		{"promotion", RoleStaff, RoleIT, []int{10}, nil, false, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queried := false
+			openAssignments := func(ctx context.Context, id int) ([]int, error) {
+				queried = true
+				return tt.ticketIDs, tt.queryErr
+			}
+
+			err := checkOpenAssignments(context.Background(), 1, tt.previousRole, tt.role, openAssignments)
+
+			if queried != tt.wantQueried {
+				t.Errorf("queried = %v, want %v", queried, tt.wantQueried)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("checkOpenAssignments() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			var openErr *OpenAssignmentsError
+			if tt.wantTickets == nil {
+				if err != nil {
+					t.Fatalf("checkOpenAssignments() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.As(err, &openErr) || !slices.Equal(openErr.TicketIDs, tt.wantTickets) {
+				t.Errorf("checkOpenAssignments() error = %v, want open tickets %v", err, tt.wantTickets)
+			}
+		})
+	}
+}
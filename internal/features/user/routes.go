@@ -2,13 +2,25 @@ package user
 
 import "github.com/labstack/echo/v5"
 
-func RegisterRoutes(g *echo.Group, handler *Handler) {
+func RegisterRoutes(g *echo.Group, handler *Handler, adminOnly echo.MiddlewareFunc) {
 	users := g.Group("/users")
 
 	users.GET("", handler.GetAll)
+	users.GET("/distinct", handler.GetDistinctValues)
 	users.GET("/:id", handler.GetByID)
+	users.GET("/:id/ticket-stats", handler.GetTicketStats)
+	users.GET("/:id/avatar", handler.GetAvatar)
+	users.GET("/:id/divisions/history", handler.GetDivisionHistory)
 	users.POST("", handler.Create)
+	users.POST("/provision", handler.CreateWithGeneratedPassword, adminOnly)
+	users.POST("/import", handler.Import, adminOnly)
+	users.POST("/check-emails", handler.CheckEmails)
+	users.POST("/exists", handler.CheckExist)
+	users.POST("/:id/reset-password", handler.ResetPassword, adminOnly)
 	users.PATCH("/:id", handler.Update)
 	users.PATCH("/:id/avatar", handler.UpdateAvatar)
+	users.PATCH("/:id/availability", handler.UpdateAvailability)
+	users.PUT("/:id/images/:slot", handler.UpdateImage)
 	users.DELETE("/:id", handler.Delete)
+	users.DELETE("/:id/images/:slot", handler.DeleteImage)
 }
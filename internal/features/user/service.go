@@ -2,27 +2,76 @@ package user
 
 import (
 	"context"
+	"crypto/rand"
 	"database/sql"
 	"errors"
-	"fmt"
 	"log/slog"
+	"math/big"
 	"strings"
+	"time"
 
 	"helpdesk/internal/features/division"
 	appErrors "helpdesk/internal/utils/errors"
+	"helpdesk/internal/utils/nullable"
 	"helpdesk/internal/utils/response"
 	"helpdesk/internal/utils/uploads"
+	"helpdesk/internal/utils/validator"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	minGeneratedPasswordLength = 12
+
+	passwordLower   = "abcdefghijkmnpqrstuvwxyz"
+	passwordUpper   = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	passwordDigits  = "23456789"
+	passwordSymbols = "!@#$%^&*-_=+?"
+)
+
 type Service interface {
 	GetAll(ctx context.Context, req *GetUsersQuery) (*response.ListResponse[UserResponse], error)
 	GetByID(ctx context.Context, id int) (*UserResponse, error)
+	GetDistinctValues(ctx context.Context, req *response.DistinctQuery) (*response.DistinctValues, error)
+	GetTicketStats(ctx context.Context, id int) (*UserTicketStatsResponse, error)
+	GetAvatarPath(ctx context.Context, id int) (string, error)
+	GetDivisionHistory(ctx context.Context, id int, req *GetDivisionHistoryQuery) (*response.ListResponse[DivisionHistoryResponse], error)
+	CheckEmails(ctx context.Context, req *CheckEmailsRequest) (*CheckEmailsResponse, error)
+	CheckExist(ctx context.Context, req *CheckUsersExistRequest) (*CheckUsersExistResponse, error)
 	Create(ctx context.Context, req *CreateUserRequest) (*UserResponse, error)
+	CreateWithGeneratedPassword(ctx context.Context, req *CreateUserRequest) (*UserResponse, error)
+	Import(ctx context.Context, req *ImportUserRequest) (*UserResponse, error)
 	Update(ctx context.Context, id int, req *UpdateUserRequest) (*UserResponse, error)
 	UpdateAvatar(ctx context.Context, id int, avatarURL string) (*UserResponse, error)
-	Delete(ctx context.Context, id int) error
+	UpdateAvailability(ctx context.Context, id int, req *UpdateAvailabilityRequest) (*UserResponse, error)
+	UpdateImage(ctx context.Context, id int, slot, imageURL string) (*UserResponse, error)
+	ResetPassword(ctx context.Context, id int) (*ResetPasswordResponse, error)
+	Delete(ctx context.Context, id int, req *DeleteUserQuery) error
+	DeleteImage(ctx context.Context, id int, slot string) (*UserResponse, error)
+}
+
+// UploadLimits caps how many files a user can own; 0 means unlimited.
+type UploadLimits struct {
+	Default int
+	Admin   int
+}
+
+// CreateDefaults fill in role and division when a create request omits them.
+// Empty values keep the fields required.
+type CreateDefaults struct {
+	Role       string
+	DivisionID int
+}
+
+// CredentialPolicy configures the password and email checks on create and
+// the length of server-generated passwords.
+type CredentialPolicy struct {
+	RejectCommonPasswords bool
+	// DisposableEmailDomains are refused as email providers; nil accepts
+	// every provider.
+	DisposableEmailDomains validator.DisposableDomains
+	// GeneratedPasswordLength is raised to 12 when lower.
+	GeneratedPasswordLength int
 }
 
 type service struct {
@@ -30,14 +79,22 @@ type service struct {
 	divisionService division.Service
 	logger          *slog.Logger
 	baseURL         string
+	uploadLimits    UploadLimits
+	createDefaults  CreateDefaults
+	credentials     CredentialPolicy
+	listOptions     response.ListOptions
 }
 
-func NewService(repo Repository, divisionService division.Service, logger *slog.Logger, baseURL string) Service {
+func NewService(repo Repository, divisionService division.Service, logger *slog.Logger, baseURL string, uploadLimits UploadLimits, createDefaults CreateDefaults, credentials CredentialPolicy, listOptions response.ListOptions) Service {
 	return &service{
 		repo:            repo,
 		divisionService: divisionService,
 		logger:          logger,
 		baseURL:         baseURL,
+		uploadLimits:    uploadLimits,
+		createDefaults:  createDefaults,
+		credentials:     credentials,
+		listOptions:     listOptions,
 	}
 }
 
@@ -46,26 +103,18 @@ func (s *service) GetAll(ctx context.Context, req *GetUsersQuery) (*response.Lis
 		req = &GetUsersQuery{}
 	}
 
-	filter, err := req.Normalize()
+	filter, err := req.Normalize(s.listOptions)
 	if err != nil {
 		return nil, err
 	}
 
 	users, totalItems, err := s.repo.GetAll(ctx, filter)
 	if err != nil {
-		s.logger.Error("failed to get users", "error", err)
-		return nil, appErrors.Internal("Failed to retrieve users")
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to get users", "Failed to retrieve users")
 	}
 
-	return &response.ListResponse[UserResponse]{
-		Items: ToUserResponses(users, s.baseURL),
-		Pagination: response.PaginationResponse{
-			Page:       filter.Page,
-			Limit:      filter.Limit,
-			TotalItems: totalItems,
-			TotalPages: response.CalculateTotalPages(totalItems, filter.Limit),
-		},
-	}, nil
+	response.WarnIfTruncated(ctx, filter.Pagination, totalItems)
+	return response.NewListResponse(ToUserResponses(users, s.baseURL), filter.Pagination, totalItems), nil
 }
 
 func (s *service) GetByID(ctx context.Context, id int) (*UserResponse, error) {
@@ -75,8 +124,7 @@ func (s *service) GetByID(ctx context.Context, id int) (*UserResponse, error) {
 
 	user, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		s.logger.Error("failed to get user", "error", err, "id", id)
-		return nil, appErrors.Internal("Failed to retrieve user")
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to get user", "Failed to retrieve user", "id", id)
 	}
 
 	if user == nil {
@@ -86,13 +134,237 @@ func (s *service) GetByID(ctx context.Context, id int) (*UserResponse, error) {
 	return ToUserResponse(user, s.baseURL), nil
 }
 
-func (s *service) Create(ctx context.Context, req *CreateUserRequest) (*UserResponse, error) {
+func (s *service) GetDistinctValues(ctx context.Context, req *response.DistinctQuery) (*response.DistinctValues, error) {
+	if err := req.Validate(DistinctFields); err != nil {
+		return nil, err
+	}
+
+	values, err := s.repo.GetDistinctValues(ctx, req.Field)
+	if err != nil {
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to get distinct user values", "Failed to retrieve distinct values", "field", req.Field)
+	}
+
+	return &response.DistinctValues{Field: req.Field, Values: values}, nil
+}
+
+func (s *service) GetTicketStats(ctx context.Context, id int) (*UserTicketStatsResponse, error) {
+	if id <= 0 {
+		return nil, appErrors.BadRequest("Invalid user ID")
+	}
+
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to get user", "Failed to retrieve ticket stats", "id", id)
+	}
+	if user == nil {
+		return nil, appErrors.NotFound("User")
+	}
+
+	created, err := s.repo.GetCreatedTicketStats(ctx, id)
+	if err != nil {
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to get created ticket stats", "Failed to retrieve ticket stats", "id", id)
+	}
+
+	result := &UserTicketStatsResponse{
+		UserID:  user.ID,
+		Created: *ToTicketStatsResponse(created),
+	}
+
+	if user.Role == RoleIT {
+		assigned, err := s.repo.GetAssignedTicketStats(ctx, id)
+		if err != nil {
+			return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to get assigned ticket stats", "Failed to retrieve ticket stats", "id", id)
+		}
+		result.Assigned = ToTicketStatsResponse(assigned)
+	}
+
+	return result, nil
+}
+
+func (s *service) GetAvatarPath(ctx context.Context, id int) (string, error) {
+	if id <= 0 {
+		return "", appErrors.BadRequest("Invalid user ID")
+	}
+
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return "", appErrors.FromRepository(ctx, s.logger, err, "failed to get user", "Failed to retrieve avatar", "id", id)
+	}
+	if user == nil {
+		return "", appErrors.NotFound("User")
+	}
+
+	if user.AvatarURL == nil || *user.AvatarURL == "" {
+		return "", appErrors.NotFound("Avatar")
+	}
+
+	return *user.AvatarURL, nil
+}
+
+func (s *service) GetDivisionHistory(ctx context.Context, id int, req *GetDivisionHistoryQuery) (*response.ListResponse[DivisionHistoryResponse], error) {
+	if id <= 0 {
+		return nil, appErrors.BadRequest("Invalid user ID")
+	}
+
+	pagination, err := req.NormalizePagination(s.listOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := s.repo.Exists(ctx, id)
+	if err != nil {
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to check user existence", "Failed to retrieve division history", "id", id)
+	}
+	if !exists {
+		return nil, appErrors.NotFound("User")
+	}
+
+	history, totalItems, err := s.repo.GetDivisionHistory(ctx, id, pagination)
+	if err != nil {
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to get division history", "Failed to retrieve division history", "id", id)
+	}
+
+	return response.NewListResponse(ToDivisionHistoryResponses(history), pagination, totalItems), nil
+}
+
+func (s *service) CheckEmails(ctx context.Context, req *CheckEmailsRequest) (*CheckEmailsResponse, error) {
+	if err := req.Validate(); err != nil {
+		s.logger.Warn("validation failed", "error", err)
+		return nil, err
+	}
+
+	normalized := make([]string, len(req.Emails))
+	for i, email := range req.Emails {
+		normalized[i] = strings.ToLower(strings.TrimSpace(email))
+	}
+
+	existing, err := s.repo.GetExistingEmails(ctx, normalized)
+	if err != nil {
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to check existing emails", "Failed to check emails")
+	}
+
+	taken := make(map[string]bool, len(existing))
+	for _, email := range existing {
+		taken[email] = true
+	}
+
+	result := &CheckEmailsResponse{Taken: []string{}, Available: []string{}}
+	for i, email := range req.Emails {
+		if taken[normalized[i]] {
+			result.Taken = append(result.Taken, email)
+		} else {
+			result.Available = append(result.Available, email)
+		}
+	}
+
+	return result, nil
+}
+
+func (s *service) CheckExist(ctx context.Context, req *CheckUsersExistRequest) (*CheckUsersExistResponse, error) {
 	if err := req.Validate(); err != nil {
 		s.logger.Warn("validation failed", "error", err)
 		return nil, err
 	}
 
-	name := strings.TrimSpace(req.Name)
+	result := &CheckUsersExistResponse{
+		IDs:    make(map[int]response.ExistenceResult, len(req.IDs)),
+		Emails: make(map[string]response.ExistenceResult, len(req.Emails)),
+	}
+
+	if len(req.IDs) > 0 {
+		users, err := s.repo.GetByIDs(ctx, req.IDs)
+		if err != nil {
+			return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to get users by IDs", "Failed to check users")
+		}
+
+		for _, id := range req.IDs {
+			result.IDs[id] = response.ExistenceResult{}
+		}
+		for _, u := range users {
+			result.IDs[u.ID] = response.ExistenceResult{Exists: true, IsActive: u.IsActive}
+		}
+	}
+
+	if len(req.Emails) > 0 {
+		normalized := make([]string, len(req.Emails))
+		for i, email := range req.Emails {
+			normalized[i] = strings.ToLower(strings.TrimSpace(email))
+		}
+
+		users, err := s.repo.GetByEmails(ctx, normalized)
+		if err != nil {
+			return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to get users by emails", "Failed to check users")
+		}
+
+		byEmail := make(map[string]response.ExistenceResult, len(users))
+		for _, u := range users {
+			byEmail[strings.ToLower(u.Email)] = response.ExistenceResult{Exists: true, IsActive: u.IsActive}
+		}
+		for i, email := range req.Emails {
+			result.Emails[email] = byEmail[normalized[i]]
+		}
+	}
+
+	return result, nil
+}
+
+func (s *service) Create(ctx context.Context, req *CreateUserRequest) (*UserResponse, error) {
+	return s.create(ctx, req, false)
+}
+
+// CreateWithGeneratedPassword backs the admin-only create flow: the server
+// picks the password and returns it once in the response.
+func (s *service) CreateWithGeneratedPassword(ctx context.Context, req *CreateUserRequest) (*UserResponse, error) {
+	if req.Password != "" {
+		return nil, appErrors.BadRequest("password must be omitted; the server generates it")
+	}
+
+	password, err := GeneratePassword(s.credentials.GeneratedPasswordLength)
+	if err != nil {
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to generate password", "Failed to create user")
+	}
+	req.Password = password
+
+	return s.create(ctx, req, true)
+}
+
+// Import backs the admin-only import route: it creates the user with the
+// createdAt of the system they are migrated from.
+func (s *service) Import(ctx context.Context, req *ImportUserRequest) (*UserResponse, error) {
+	s.applyCreateDefaults(&req.CreateUserRequest)
+
+	if err := req.Validate(s.credentials, time.Now()); err != nil {
+		s.logger.Warn("validation failed", "error", err)
+		return nil, err
+	}
+
+	return s.insert(ctx, &req.CreateUserRequest, false, req.CreatedAt)
+}
+
+func (s *service) create(ctx context.Context, req *CreateUserRequest, generated bool) (*UserResponse, error) {
+	s.applyCreateDefaults(req)
+
+	if err := req.Validate(s.credentials); err != nil {
+		s.logger.Warn("validation failed", "error", err)
+		return nil, err
+	}
+
+	return s.insert(ctx, req, generated, nil)
+}
+
+func (s *service) applyCreateDefaults(req *CreateUserRequest) {
+	if strings.TrimSpace(req.Role) == "" {
+		req.Role = s.createDefaults.Role
+	}
+	if req.DivisionID == 0 {
+		req.DivisionID = s.createDefaults.DivisionID
+	}
+}
+
+// insert creates a validated user; a nil createdAt keeps the insert time.
+func (s *service) insert(ctx context.Context, req *CreateUserRequest, generated bool, createdAt *time.Time) (*UserResponse, error) {
+	username := strings.TrimSpace(req.Username)
+	displayName := strings.TrimSpace(req.DisplayName)
 	email := strings.TrimSpace(req.Email)
 
 	if err := s.divisionService.ValidateForAssignment(ctx, req.DivisionID); err != nil {
@@ -101,32 +373,45 @@ func (s *service) Create(ctx context.Context, req *CreateUserRequest) (*UserResp
 
 	existing, err := s.repo.GetByEmail(ctx, email)
 	if err != nil {
-		s.logger.Error("failed to check existing user", "error", err)
-		return nil, appErrors.Internal("Failed to create user")
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to check existing user", "Failed to create user")
+	}
+	if existing != nil {
+		return nil, appErrors.AlreadyExistsWithID("User with this email", existing.ID)
+	}
+
+	existing, err = s.repo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to check existing user", "Failed to create user")
 	}
 	if existing != nil {
-		return nil, appErrors.AlreadyExists("User with this email")
+		return nil, appErrors.AlreadyExistsWithID("User with this username", existing.ID)
 	}
 
 	passwordHash, err := hashPassword(req.Password)
 	if err != nil {
-		s.logger.Error("failed to hash password", "error", err)
-		return nil, appErrors.Internal("Failed to create user")
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to hash password", "Failed to create user")
 	}
 
 	role := strings.TrimSpace(req.Role)
 
-	user, err := s.repo.Create(ctx, name, email, passwordHash, "", "", role, req.DivisionID)
+	user, err := s.repo.Create(ctx, username, displayName, email, passwordHash, "", "", role, req.DivisionID, createdAt)
 	if err != nil {
-		s.logger.Error("failed to create user", "error", err, "email", email)
+		if strings.Contains(err.Error(), "username") && strings.Contains(err.Error(), "already exists") {
+			return nil, s.alreadyExists(ctx, "User with this username", s.repo.GetByUsername, username)
+		}
 		if strings.Contains(err.Error(), "already exists") {
-			return nil, appErrors.AlreadyExists("User with this email")
+			return nil, s.alreadyExists(ctx, "User with this email", s.repo.GetByEmail, email)
 		}
-		return nil, appErrors.Internal("Failed to create user")
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to create user", "Failed to create user", "email", email)
 	}
 
-	s.logger.Info("user created", "id", user.ID, "email", user.Email)
-	return ToUserResponse(user, s.baseURL), nil
+	s.logger.Info("user created", "id", user.ID, "email", user.Email, "generatedPassword", generated, "imported", createdAt != nil)
+
+	resp := ToUserResponse(user, s.baseURL)
+	if generated {
+		resp.GeneratedPassword = req.Password
+	}
+	return resp, nil
 }
 
 func (s *service) Update(ctx context.Context, id int, req *UpdateUserRequest) (*UserResponse, error) {
@@ -141,8 +426,7 @@ func (s *service) Update(ctx context.Context, id int, req *UpdateUserRequest) (*
 
 	exists, err := s.repo.Exists(ctx, id)
 	if err != nil {
-		s.logger.Error("failed to check user existence", "error", err, "id", id)
-		return nil, appErrors.Internal("Failed to update user")
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to check user existence", "Failed to update user", "id", id)
 	}
 	if !exists {
 		return nil, appErrors.NotFound("User")
@@ -154,20 +438,25 @@ func (s *service) Update(ctx context.Context, id int, req *UpdateUserRequest) (*
 
 	currentUser, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		s.logger.Error("failed to get current user", "error", err, "id", id)
-		return nil, appErrors.Internal("Failed to update user")
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to get current user", "Failed to update user", "id", id)
 	}
 	if currentUser == nil {
 		return nil, appErrors.NotFound("User")
 	}
 
-	name := strings.TrimSpace(req.Name)
+	username := strings.TrimSpace(req.Username)
+	displayName := strings.TrimSpace(req.DisplayName)
 
-	phone := ""
-	if req.Phone != nil {
-		phone = strings.TrimSpace(*req.Phone)
+	existing, err := s.repo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to check existing user", "Failed to update user", "id", id)
+	}
+	if existing != nil && existing.ID != id {
+		return nil, appErrors.AlreadyExistsWithID("User with this username", existing.ID)
 	}
 
+	phone := resolvePhone(currentUser.Phone, req.Phone)
+
 	role := strings.TrimSpace(req.Role)
 
 	isActive := currentUser.IsActive
@@ -175,16 +464,32 @@ func (s *service) Update(ctx context.Context, id int, req *UpdateUserRequest) (*
 		isActive = *req.IsActive
 	}
 
-	user, err := s.repo.Update(ctx, id, name, phone, role, req.DivisionID, isActive)
+	clearAvatar := req.AvatarURL.IsNull() && currentUser.AvatarURL != nil && *currentUser.AvatarURL != ""
+
+	user, err := s.repo.Update(ctx, id, username, displayName, phone, role, req.DivisionID, isActive, clearAvatar)
 	if err != nil {
-		s.logger.Error("failed to update user", "error", err, "id", id)
-		return nil, appErrors.Internal("Failed to update user")
+		if strings.Contains(err.Error(), "already exists") {
+			return nil, s.alreadyExists(ctx, "User with this username", s.repo.GetByUsername, username)
+		}
+		var openErr *OpenAssignmentsError
+		if errors.As(err, &openErr) {
+			return nil, appErrors.Conflict("Reassign the user's open tickets before changing their role to STAFF").WithDetails(map[string]interface{}{
+				"ticketIds": openErr.TicketIDs,
+			})
+		}
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to update user", "Failed to update user", "id", id)
 	}
 
 	if user == nil {
 		return nil, appErrors.NotFound("User")
 	}
 
+	if clearAvatar {
+		if err := uploads.DeleteFile(*currentUser.AvatarURL); err != nil {
+			s.logger.Warn("failed to delete cleared avatar", "error", err, "path", *currentUser.AvatarURL)
+		}
+	}
+
 	s.logger.Info("user updated", "id", user.ID, "email", user.Email)
 	return ToUserResponse(user, s.baseURL), nil
 }
@@ -200,17 +505,21 @@ func (s *service) UpdateAvatar(ctx context.Context, id int, avatarURL string) (*
 
 	oldUser, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		s.logger.Error("failed to get user", "error", err, "id", id)
-		return nil, appErrors.Internal("Failed to update avatar")
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to get user", "Failed to update avatar", "id", id)
 	}
 	if oldUser == nil {
 		return nil, appErrors.NotFound("User")
 	}
 
+	if oldUser.AvatarURL == nil || *oldUser.AvatarURL == "" {
+		if err := s.checkUploadLimit(ctx, oldUser); err != nil {
+			return nil, err
+		}
+	}
+
 	user, err := s.repo.UpdateAvatar(ctx, id, avatarURL)
 	if err != nil {
-		s.logger.Error("failed to update avatar", "error", err, "id", id)
-		return nil, appErrors.Internal("Failed to update avatar")
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to update avatar", "Failed to update avatar", "id", id)
 	}
 
 	if user == nil {
@@ -227,27 +536,158 @@ func (s *service) UpdateAvatar(ctx context.Context, id int, avatarURL string) (*
 	return ToUserResponse(user, s.baseURL), nil
 }
 
-func (s *service) Delete(ctx context.Context, id int) error {
+func (s *service) UpdateAvailability(ctx context.Context, id int, req *UpdateAvailabilityRequest) (*UserResponse, error) {
+	if id <= 0 {
+		return nil, appErrors.BadRequest("Invalid user ID")
+	}
+
+	if err := req.Validate(); err != nil {
+		s.logger.Warn("validation failed", "error", err)
+		return nil, err
+	}
+
+	currentUser, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to get user", "Failed to update availability", "id", id)
+	}
+	if currentUser == nil {
+		return nil, appErrors.NotFound("User")
+	}
+	if currentUser.Role == RoleStaff {
+		return nil, appErrors.BadRequest("Availability only applies to IT and ADMIN users")
+	}
+
+	user, err := s.repo.UpdateAvailability(ctx, id, *req.IsAvailable)
+	if err != nil {
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to update availability", "Failed to update availability", "id", id)
+	}
+	if user == nil {
+		return nil, appErrors.NotFound("User")
+	}
+
+	s.logger.Info("user availability updated", "id", user.ID, "isAvailable", user.IsAvailable)
+	return ToUserResponse(user, s.baseURL), nil
+}
+
+func (s *service) UpdateImage(ctx context.Context, id int, slot, imageURL string) (*UserResponse, error) {
+	if err := validateImageSlot(slot); err != nil {
+		return nil, err
+	}
+
+	if slot == ImageSlotAvatar {
+		return s.UpdateAvatar(ctx, id, imageURL)
+	}
+
+	if id <= 0 {
+		return nil, appErrors.BadRequest("Invalid user ID")
+	}
+
+	if imageURL == "" {
+		return nil, appErrors.BadRequest("Image URL is required")
+	}
+
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to get user", "Failed to update image", "id", id)
+	}
+	if user == nil {
+		return nil, appErrors.NotFound("User")
+	}
+
+	oldImage, err := s.repo.GetImage(ctx, id, slot)
+	if err != nil {
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to get user image", "Failed to update image", "id", id, "slot", slot)
+	}
+
+	if oldImage == nil {
+		if err := s.checkUploadLimit(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.UpsertImage(ctx, id, slot, imageURL); err != nil {
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to save user image", "Failed to update image", "id", id, "slot", slot)
+	}
+
+	if oldImage != nil {
+		if err := uploads.DeleteFile(oldImage.ImageURL); err != nil {
+			s.logger.Warn("failed to delete old image", "error", err, "path", oldImage.ImageURL)
+		}
+	}
+
+	s.logger.Info("user image updated", "id", id, "slot", slot)
+	return s.GetByID(ctx, id)
+}
+
+func (s *service) ResetPassword(ctx context.Context, id int) (*ResetPasswordResponse, error) {
+	if id <= 0 {
+		return nil, appErrors.BadRequest("Invalid user ID")
+	}
+
+	password, err := GeneratePassword(s.credentials.GeneratedPasswordLength)
+	if err != nil {
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to generate password", "Failed to reset password", "id", id)
+	}
+
+	passwordHash, err := hashPassword(password)
+	if err != nil {
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to hash password", "Failed to reset password", "id", id)
+	}
+
+	updated, err := s.repo.UpdatePassword(ctx, id, passwordHash)
+	if err != nil {
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to update password", "Failed to reset password", "id", id)
+	}
+	if !updated {
+		return nil, appErrors.NotFound("User")
+	}
+
+	s.logger.Info("user password reset", "id", id)
+	return &ResetPasswordResponse{ID: id, Password: password}, nil
+}
+
+func (s *service) Delete(ctx context.Context, id int, req *DeleteUserQuery) error {
 	if id <= 0 {
 		return appErrors.BadRequest("Invalid user ID")
 	}
 
+	if req.ReassignCreatedTo < 0 || req.ReassignCreatedTo == id {
+		return appErrors.BadRequest("reassignCreatedTo must be the ID of another user")
+	}
+	if req.ReassignCreatedTo > 0 {
+		exists, err := s.repo.Exists(ctx, req.ReassignCreatedTo)
+		if err != nil {
+			return appErrors.FromRepository(ctx, s.logger, err, "failed to check reassign target", "Failed to delete user", "id", id)
+		}
+		if !exists {
+			return appErrors.BadRequest("reassignCreatedTo user does not exist")
+		}
+	}
+
 	user, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		s.logger.Error("failed to get user", "error", err, "id", id)
-		return appErrors.Internal("Failed to delete user")
+		return appErrors.FromRepository(ctx, s.logger, err, "failed to get user", "Failed to delete user", "id", id)
 	}
 	if user == nil {
 		return appErrors.NotFound("User")
 	}
 
-	err = s.repo.Delete(ctx, id)
+	err = s.repo.Delete(ctx, id, req.ReassignCreatedTo)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return appErrors.NotFound("User")
 		}
-		s.logger.Error("failed to delete user", "error", err, "id", id)
-		return appErrors.Internal(fmt.Sprintf("Failed to delete user: %v", err))
+		var createdErr *CreatedTicketsError
+		if errors.As(err, &createdErr) {
+			conflict := appErrors.Conflict("User has created tickets; pass reassignCreatedTo to move them to another user")
+			if createdErr.Count > 0 {
+				conflict = conflict.WithDetails(map[string]interface{}{
+					"ticketCount": createdErr.Count,
+				})
+			}
+			return conflict
+		}
+		return appErrors.FromRepository(ctx, s.logger, err, "failed to delete user", "Failed to delete user", "id", id)
 	}
 
 	if user.AvatarURL != nil && *user.AvatarURL != "" {
@@ -256,7 +696,126 @@ func (s *service) Delete(ctx context.Context, id int) error {
 		}
 	}
 
-	s.logger.Info("user deleted", "id", id)
+	for slot, imagePath := range user.Images {
+		if err := uploads.DeleteFile(imagePath); err != nil {
+			s.logger.Warn("failed to delete user image", "error", err, "path", imagePath, "slot", slot)
+		}
+	}
+
+	s.logger.Info("user deleted", "id", id, "reassignCreatedTo", req.ReassignCreatedTo)
+	return nil
+}
+
+func (s *service) DeleteImage(ctx context.Context, id int, slot string) (*UserResponse, error) {
+	if err := validateImageSlot(slot); err != nil {
+		return nil, err
+	}
+
+	if id <= 0 {
+		return nil, appErrors.BadRequest("Invalid user ID")
+	}
+
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to get user", "Failed to delete image", "id", id)
+	}
+	if user == nil {
+		return nil, appErrors.NotFound("User")
+	}
+
+	var imagePath string
+	if slot == ImageSlotAvatar {
+		if user.AvatarURL == nil || *user.AvatarURL == "" {
+			return nil, appErrors.NotFound("Image")
+		}
+		imagePath = *user.AvatarURL
+
+		if _, err := s.repo.UpdateAvatar(ctx, id, ""); err != nil {
+			return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to clear avatar", "Failed to delete image", "id", id)
+		}
+	} else {
+		url, ok := user.Images[slot]
+		if !ok {
+			return nil, appErrors.NotFound("Image")
+		}
+		imagePath = url
+
+		if err := s.repo.DeleteImage(ctx, id, slot); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, appErrors.NotFound("Image")
+			}
+			return nil, appErrors.FromRepository(ctx, s.logger, err, "failed to delete user image", "Failed to delete image", "id", id, "slot", slot)
+		}
+	}
+
+	if err := uploads.DeleteFile(imagePath); err != nil {
+		s.logger.Warn("failed to delete image file", "error", err, "path", imagePath)
+	}
+
+	s.logger.Info("user image deleted", "id", id, "slot", slot)
+	return s.GetByID(ctx, id)
+}
+
+// resolvePhone keeps the current phone when the field is omitted, clears it
+// on null or an empty string, and sets it otherwise.
+func resolvePhone(current *string, requested nullable.Field[string]) *string {
+	if !requested.Set {
+		return current
+	}
+
+	phone := strings.TrimSpace(requested.Value)
+	if !requested.Valid || phone == "" {
+		return nil
+	}
+	return &phone
+}
+
+// checkUploadLimit rejects a new file once the user owns as many as their
+// role allows. Replacing an existing file doesn't need a check.
+func (s *service) checkUploadLimit(ctx context.Context, user *UserWithDivision) error {
+	limit := s.uploadLimits.Default
+	if user.Role == RoleAdmin {
+		limit = s.uploadLimits.Admin
+	}
+	if limit <= 0 {
+		return nil
+	}
+
+	count, err := s.repo.CountUploads(ctx, user.ID)
+	if err != nil {
+		return appErrors.FromRepository(ctx, s.logger, err, "failed to count user uploads", "Failed to check upload limit", "id", user.ID)
+	}
+
+	if count >= limit {
+		return appErrors.BadRequest("Upload limit reached").WithDetails(map[string]interface{}{
+			"count": count,
+			"limit": limit,
+		})
+	}
+
+	return nil
+}
+
+// alreadyExists handles a unique-index violation from a concurrent write that
+// the lookup before it missed: it re-reads the winning user with lookup so the
+// conflict carries their ID like the non-racing path.
+func (s *service) alreadyExists(ctx context.Context, resource string, lookup func(context.Context, string) (*User, error), value string) error {
+	existing, err := lookup(ctx, value)
+	if err != nil {
+		return appErrors.FromRepository(ctx, s.logger, err, "failed to get user", "Failed to save user")
+	}
+	if existing == nil {
+		return appErrors.Conflict("User was modified concurrently, retry the request")
+	}
+	return appErrors.AlreadyExistsWithID(resource, existing.ID)
+}
+
+func validateImageSlot(slot string) error {
+	v := validator.New()
+	validator.ValidateEnum(v, "slot", slot, ValidImageSlots, true)
+	if !v.Valid() {
+		return v.ToAppError()
+	}
 	return nil
 }
 
@@ -268,6 +827,51 @@ func hashPassword(password string) (string, error) {
 	return string(hash), nil
 }
 
+// GeneratePassword returns a random password of length characters (at least
+// 12) with at least one lowercase, uppercase, digit and symbol character.
+// Look-alike characters are left out of the charset so the password can be
+// read back to a user.
+func GeneratePassword(length int) (string, error) {
+	classes := []string{passwordLower, passwordUpper, passwordDigits, passwordSymbols}
+	charset := strings.Join(classes, "")
+	length = max(length, minGeneratedPasswordLength)
+
+	for {
+		password := make([]byte, length)
+		for i := range password {
+			set := charset
+			if i < len(classes) {
+				set = classes[i]
+			}
+			c, err := randomChar(set)
+			if err != nil {
+				return "", err
+			}
+			password[i] = c
+		}
+
+		for i := len(password) - 1; i > 0; i-- {
+			j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+			if err != nil {
+				return "", err
+			}
+			password[i], password[j.Int64()] = password[j.Int64()], password[i]
+		}
+
+		if !validator.IsCommonPassword(string(password)) {
+			return string(password), nil
+		}
+	}
+}
+
+func randomChar(set string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(set))))
+	if err != nil {
+		return 0, err
+	}
+	return set[n.Int64()], nil
+}
+
 func VerifyPassword(hash, password string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	return err == nil
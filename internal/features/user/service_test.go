@@ -0,0 +1,472 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"helpdesk/internal/features/division"
+	appErrors "helpdesk/internal/utils/errors"
+	"helpdesk/internal/utils/nullable"
+	"helpdesk/internal/utils/response"
+)
+
+// fakeRepository keeps users in memory. Its Update runs the repository's own
+// checkOpenAssignments against openTickets instead of the tickets table.
+// Methods the tests don't need panic via the nil embedded interface.
+type fakeRepository struct {
+	Repository
+	users       map[int]*UserWithDivision
+	openTickets map[int][]int
+	deleteErr   error
+	// raced is inserted by the next Create, which then fails the way the
+	// unique email index does when a concurrent request won.
+	raced *UserWithDivision
+}
+
+func (r *fakeRepository) Exists(ctx context.Context, id int) (bool, error) {
+	_, ok := r.users[id]
+	return ok, nil
+}
+
+func (r *fakeRepository) GetByID(ctx context.Context, id int) (*UserWithDivision, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (r *fakeRepository) GetByEmail(ctx context.Context, email string) (*User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return &User{ID: user.ID, Email: user.Email}, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeRepository) GetByUsername(ctx context.Context, username string) (*User, error) {
+	for _, user := range r.users {
+		if user.Username == username {
+			return &User{ID: user.ID, Username: user.Username}, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeRepository) Create(ctx context.Context, username, displayName, email, passwordHash string, avatarURL, phone, role string, divisionID int, createdAt *time.Time) (*UserWithDivision, error) {
+	if r.raced != nil {
+		r.users[r.raced.ID] = r.raced
+		r.raced = nil
+		return nil, fmt.Errorf("user with email '%s' already exists", email)
+	}
+
+	user := &UserWithDivision{ID: len(r.users) + 1, Username: username, DisplayName: displayName, Email: email, Role: role, DivisionID: divisionID, IsActive: true, CreatedAt: time.Now()}
+	if createdAt != nil {
+		user.CreatedAt = *createdAt
+	}
+	r.users[user.ID] = user
+	return r.GetByID(ctx, user.ID)
+}
+
+func (r *fakeRepository) Update(ctx context.Context, id int, username, displayName string, phone *string, role string, divisionID int, isActive, clearAvatar bool) (*UserWithDivision, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, nil
+	}
+
+	if err := checkOpenAssignments(ctx, id, user.Role, role, r.openAssignments); err != nil {
+		return nil, err
+	}
+
+	user.Username = username
+	user.DisplayName = displayName
+	user.Phone = phone
+	user.Role = role
+	user.DivisionID = divisionID
+	user.IsActive = isActive
+	if clearAvatar {
+		user.AvatarURL = nil
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r *fakeRepository) Delete(ctx context.Context, id, reassignCreatedTo int) error {
+	if r.deleteErr != nil {
+		return r.deleteErr
+	}
+	delete(r.users, id)
+	return nil
+}
+
+func (r *fakeRepository) openAssignments(ctx context.Context, id int) ([]int, error) {
+	return r.openTickets[id], nil
+}
+
+type fakeDivisionService struct {
+	division.Service
+}
+
+func (fakeDivisionService) ValidateForAssignment(ctx context.Context, id int) error {
+	return nil
+}
+
+func newTestService(users ...UserWithDivision) (Service, *fakeRepository) {
+	repo := &fakeRepository{
+		users:       make(map[int]*UserWithDivision),
+		openTickets: make(map[int][]int),
+	}
+	for i := range users {
+		repo.users[users[i].ID] = &users[i]
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewService(repo, fakeDivisionService{}, logger, "http://localhost:8080", UploadLimits{}, CreateDefaults{}, CredentialPolicy{RejectCommonPasswords: true}, response.ListOptions{}), repo
+}
+
+func testUser(id int, role string) UserWithDivision {
+	return UserWithDivision{
+		ID:          id,
+		Username:    "tester",
+		DisplayName: "Test User",
+		Email:       "user@example.com",
+		Phone:       strPtr("08123456789"),
+		Role:        role,
+		DivisionID:  1,
+		IsActive:    true,
+	}
+}
+
+func updateRequest(user UserWithDivision, role string) *UpdateUserRequest {
+	return &UpdateUserRequest{
+		Username:    user.Username,
+		DisplayName: user.DisplayName,
+		Role:        role,
+		DivisionID:  user.DivisionID,
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestResolvePhone(t *testing.T) {
+	current := strPtr("08123456789")
+
+	tests := []struct {
+		name      string
+		requested nullable.Field[string]
+		want      *string
+	}{
+		{"omitted keeps current", nullable.Field[string]{}, current},
+		{"empty string clears", nullable.Field[string]{Set: true, Valid: true, Value: ""}, nil},
+		{"value sets", nullable.Field[string]{Set: true, Valid: true, Value: " 08987654321 "}, strPtr("08987654321")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resolvePhone(current, tt.requested)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("resolvePhone() = %v, want %v", formatPhone(got), formatPhone(tt.want))
+			}
+		})
+	}
+}
+
+func formatPhone(phone *string) string {
+	if phone == nil {
+		return "<nil>"
+	}
+	return *phone
+}
+
+func TestUpdateRoleToStaffBlockedByOpenTickets(t *testing.T) {
+	itUser := testUser(1, RoleIT)
+	svc, repo := newTestService(itUser)
+	repo.openTickets[1] = []int{10, 11}
+
+	_, err := svc.Update(context.Background(), 1, updateRequest(itUser, RoleStaff))
+
+	var appErr *appErrors.AppError
+	if !errors.As(err, &appErr) || appErr.StatusCode != http.StatusConflict {
+		t.Fatalf("Update() error = %v, want 409 conflict", err)
+	}
+	if ids, _ := appErr.Details["ticketIds"].([]int); !slices.Equal(ids, []int{10, 11}) {
+		t.Errorf("details.ticketIds = %v, want [10 11]", appErr.Details["ticketIds"])
+	}
+	if repo.users[1].Role != RoleIT {
+		t.Errorf("role = %q after blocked update, want %q", repo.users[1].Role, RoleIT)
+	}
+}
+
+func TestUpdateRoleToStaffAllowedAfterReassignment(t *testing.T) {
+	itUser := testUser(1, RoleIT)
+	svc, repo := newTestService(itUser)
+	repo.openTickets[1] = []int{10}
+
+	if _, err := svc.Update(context.Background(), 1, updateRequest(itUser, RoleStaff)); err == nil {
+		t.Fatal("Update() succeeded with open tickets, want conflict")
+	}
+
+	delete(repo.openTickets, 1)
+
+	user, err := svc.Update(context.Background(), 1, updateRequest(itUser, RoleStaff))
+	if err != nil {
+		t.Fatalf("Update() after reassignment error = %v", err)
+	}
+	if user.Role != RoleStaff {
+		t.Errorf("role = %q, want %q", user.Role, RoleStaff)
+	}
+}
+
+func TestUpdateStaffUserWithOpenTicketsKeepsRole(t *testing.T) {
+	staffUser := testUser(1, RoleStaff)
+	svc, repo := newTestService(staffUser)
+	repo.openTickets[1] = []int{10}
+
+	req := updateRequest(staffUser, RoleStaff)
+	req.Phone = nullable.Field[string]{Set: true, Valid: true, Value: "08987654321"}
+
+	user, err := svc.Update(context.Background(), 1, req)
+	if err != nil {
+		t.Fatalf("Update() error = %v, want editing a STAFF user to succeed", err)
+	}
+	if user.Phone == nil || *user.Phone != "08987654321" {
+		t.Errorf("phone = %v, want 08987654321", formatPhone(user.Phone))
+	}
+}
+
+func TestIsDowngradeToStaff(t *testing.T) {
+	tests := []struct {
+		previousRole, role string
+		want               bool
+	}{
+		{RoleIT, RoleStaff, true},
+		{RoleAdmin, RoleStaff, true},
+		{RoleStaff, RoleStaff, false},
+		{RoleStaff, RoleIT, false},
+		{RoleIT, RoleIT, false},
+	}
+
+	for _, tt := range tests {
+		if got := isDowngradeToStaff(tt.previousRole, tt.role); got != tt.want {
+			t.Errorf("isDowngradeToStaff(%q, %q) = %v, want %v", tt.previousRole, tt.role, got, tt.want)
+		}
+	}
+}
+
+func patchRequest(t *testing.T, user UserWithDivision, extra string) *UpdateUserRequest {
+	t.Helper()
+
+	body := `{"username": "` + user.Username + `", "displayName": "` + user.DisplayName + `", "role": "` + user.Role + `", "divisionId": 1` + extra + `}`
+
+	var req UpdateUserRequest
+	if err := json.Unmarshal([]byte(body), &req); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	return &req
+}
+
+func TestUpdatePatchPhone(t *testing.T) {
+	tests := []struct {
+		name  string
+		extra string
+		want  *string
+	}{
+		{"omitted keeps phone", ``, strPtr("08123456789")},
+		{"null clears phone", `, "phone": null`, nil},
+		{"value sets phone", `, "phone": "08987654321"`, strPtr("08987654321")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := testUser(1, RoleStaff)
+			svc, _ := newTestService(current)
+
+			user, err := svc.Update(context.Background(), 1, patchRequest(t, current, tt.extra))
+			if err != nil {
+				t.Fatalf("Update() error = %v", err)
+			}
+			if (user.Phone == nil) != (tt.want == nil) || (user.Phone != nil && *user.Phone != *tt.want) {
+				t.Errorf("phone = %v, want %v", formatPhone(user.Phone), formatPhone(tt.want))
+			}
+		})
+	}
+}
+
+func TestUpdatePatchAvatarURL(t *testing.T) {
+	tests := []struct {
+		name       string
+		extra      string
+		wantAvatar bool
+		wantErr    bool
+	}{
+		{"omitted keeps avatar", ``, true, false},
+		{"null clears avatar", `, "avatarUrl": null`, false, false},
+		{"value is rejected", `, "avatarUrl": "/uploads/avatars/other.png"`, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := testUser(1, RoleStaff)
+			current.AvatarURL = strPtr("uploads/avatars/missing-test-avatar.png")
+			svc, repo := newTestService(current)
+
+			_, err := svc.Update(context.Background(), 1, patchRequest(t, current, tt.extra))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Update() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if hasAvatar := repo.users[1].AvatarURL != nil; hasAvatar != tt.wantAvatar {
+				t.Errorf("has avatar = %v, want %v", hasAvatar, tt.wantAvatar)
+			}
+		})
+	}
+}
+
+func TestCreateRequiresPassword(t *testing.T) {
+	svc, _ := newTestService()
+
+	req := &CreateUserRequest{Username: "newuser", DisplayName: "New User", Email: "new@example.com", Role: RoleStaff, DivisionID: 1}
+	_, err := svc.Create(context.Background(), req)
+
+	var appErr *appErrors.AppError
+	if !errors.As(err, &appErr) || appErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("Create() error = %v, want 400", err)
+	}
+	if _, ok := appErr.Details["password"]; !ok {
+		t.Errorf("details = %v, want a password error", appErr.Details)
+	}
+}
+
+func TestCreateWithGeneratedPasswordRejectsPassword(t *testing.T) {
+	svc, _ := newTestService()
+
+	req := &CreateUserRequest{Username: "newuser", DisplayName: "New User", Email: "new@example.com", Password: "Chosen-Pass-123", Role: RoleStaff, DivisionID: 1}
+	_, err := svc.CreateWithGeneratedPassword(context.Background(), req)
+
+	var appErr *appErrors.AppError
+	if !errors.As(err, &appErr) || appErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("CreateWithGeneratedPassword() error = %v, want 400", err)
+	}
+}
+
+func TestDeleteBlockedByCreatedTickets(t *testing.T) {
+	tests := []struct {
+		name          string
+		count         int
+		wantInDetails bool
+	}{
+		{"counted", 3, true},
+		{"count unknown", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo := newTestService(testUser(1, RoleStaff))
+			repo.deleteErr = &CreatedTicketsError{Count: tt.count}
+
+			err := svc.Delete(context.Background(), 1, &DeleteUserQuery{})
+
+			var appErr *appErrors.AppError
+			if !errors.As(err, &appErr) || appErr.StatusCode != http.StatusConflict {
+				t.Fatalf("Delete() error = %v, want 409", err)
+			}
+			count, ok := appErr.Details["ticketCount"]
+			if ok != tt.wantInDetails {
+				t.Fatalf("details = %v, want ticketCount present %v", appErr.Details, tt.wantInDetails)
+			}
+			if ok && count != tt.count {
+				t.Errorf("ticketCount = %v, want %d", count, tt.count)
+			}
+		})
+	}
+}
+
+func TestImportBackdatesCreatedAt(t *testing.T) {
+	past := time.Date(2020, 3, 1, 9, 0, 0, 0, time.UTC)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		createdAt  *time.Time
+		wantStatus int
+	}{
+		{"missing createdAt", nil, http.StatusBadRequest},
+		{"future createdAt", &future, http.StatusBadRequest},
+		{"past createdAt", &past, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := newTestService()
+
+			req := &ImportUserRequest{
+				CreateUserRequest: CreateUserRequest{Username: "imported", DisplayName: "Imported User", Email: "imported@example.com", Password: "Chosen-Pass-123", Role: RoleStaff, DivisionID: 1},
+				CreatedAt:         tt.createdAt,
+			}
+			got, err := svc.Import(context.Background(), req)
+
+			if tt.wantStatus != 0 {
+				var appErr *appErrors.AppError
+				if !errors.As(err, &appErr) || appErr.StatusCode != tt.wantStatus {
+					t.Fatalf("Import() error = %v, want %d", err, tt.wantStatus)
+				}
+				if _, ok := appErr.Details["createdAt"]; !ok {
+					t.Errorf("details = %v, want a createdAt error", appErr.Details)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Import() error = %v", err)
+			}
+			if !got.CreatedAt.Equal(past) {
+				t.Errorf("createdAt = %v, want %v", got.CreatedAt, past)
+			}
+		})
+	}
+}
+
+func TestCreateIgnoresCreatedAt(t *testing.T) {
+	svc, _ := newTestService()
+
+	var req CreateUserRequest
+	body := `{"username":"newuser","displayName":"New User","email":"new@example.com","password":"Chosen-Pass-123","role":"STAFF","divisionId":1,"createdAt":"2020-03-01T09:00:00Z"}`
+	if err := json.Unmarshal([]byte(body), &req); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	got, err := svc.Create(context.Background(), &req)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if got.CreatedAt.Year() == 2020 {
+		t.Errorf("createdAt = %v, want the insert time", got.CreatedAt)
+	}
+}
+
+func TestCreateRacedByEmailReturnsExistingID(t *testing.T) {
+	svc, repo := newTestService()
+	winner := testUser(7, RoleStaff)
+	winner.Email = "new@example.com"
+	repo.raced = &winner
+
+	req := &CreateUserRequest{Username: "newuser", DisplayName: "New User", Email: "new@example.com", Password: "Chosen-Pass-123", Role: RoleStaff, DivisionID: 1}
+	_, err := svc.Create(context.Background(), req)
+
+	var appErr *appErrors.AppError
+	if !errors.As(err, &appErr) || appErr.StatusCode != http.StatusConflict {
+		t.Fatalf("Create() error = %v, want 409", err)
+	}
+	if got := appErr.Details["existingId"]; got != 7 {
+		t.Errorf("details.existingId = %v, want 7", got)
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"crypto/subtle"
+
+	appErrors "helpdesk/internal/utils/errors"
+	"helpdesk/internal/utils/response"
+
+	"github.com/labstack/echo/v5"
+)
+
+// AdminKey stands in for an ADMIN role check until the API has user
+// authentication: requests must send the operator key in X-Admin-Key. With no
+// key configured every request is rejected, so admin routes are off by default.
+func AdminKey(key string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			if key == "" {
+				return response.Error(c, appErrors.Forbidden("Admin endpoints are disabled; set ADMIN_API_KEY to enable them"))
+			}
+
+			provided := c.Request().Header.Get("X-Admin-Key")
+			if subtle.ConstantTimeCompare([]byte(provided), []byte(key)) != 1 {
+				return response.Error(c, appErrors.Unauthorized("Missing or invalid admin key"))
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"helpdesk/internal/utils/response"
+
+	"github.com/labstack/echo/v5"
+)
+
+func TestReadOnlyToggleRequiresAdminKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		key        string
+		header     string
+		wantStatus int
+	}{
+		{"no key configured", "", "anything", http.StatusForbidden},
+		{"missing key", "operator-key", "", http.StatusUnauthorized},
+		{"wrong key", "operator-key", "guess", http.StatusUnauthorized},
+		{"valid key", "operator-key", "operator-key", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			readOnly := NewReadOnlyMode(true, "/api/v1/admin/read-only")
+
+			e := echo.New()
+			e.Use(readOnly.Middleware())
+			admin := e.Group("/api/v1/admin", AdminKey(tt.key))
+			admin.PUT("/read-only", func(c *echo.Context) error {
+				readOnly.SetEnabled(false)
+				return c.NoContent(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/read-only", nil)
+			if tt.header != "" {
+				req.Header.Set("X-Admin-Key", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if wantEnabled := tt.wantStatus != http.StatusOK; readOnly.Enabled() != wantEnabled {
+				t.Errorf("read-only enabled = %v, want %v", readOnly.Enabled(), wantEnabled)
+			}
+		})
+	}
+}
+
+func TestHardDeleteRequiresAdminKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		defaultMode string
+		query       string
+		header      string
+		wantStatus  int
+	}{
+		{"soft by default", response.DeleteModeSoft, "", "", http.StatusNoContent},
+		{"explicit soft", response.DeleteModeHard, "?mode=soft", "", http.StatusNoContent},
+		{"explicit hard without key", response.DeleteModeSoft, "?mode=hard", "", http.StatusUnauthorized},
+		{"hard by default without key", response.DeleteModeHard, "", "", http.StatusUnauthorized},
+		{"explicit hard with key", response.DeleteModeSoft, "?mode=hard", "operator-key", http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			hardDeleteOnly := When(response.HardDeleteRequested(tt.defaultMode), AdminKey("operator-key"))
+			e.DELETE("/categories/:id", func(c *echo.Context) error {
+				return c.NoContent(http.StatusNoContent)
+			}, hardDeleteOnly)
+
+			req := httptest.NewRequest(http.MethodDelete, "/categories/1"+tt.query, nil)
+			if tt.header != "" {
+				req.Header.Set("X-Admin-Key", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestUnpaginatedListRequiresAdminKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		header     string
+		wantStatus int
+	}{
+		{"paginated", "?page=2", "", http.StatusOK},
+		{"all=false", "?all=false", "", http.StatusOK},
+		{"all=true without key", "?all=true", "", http.StatusUnauthorized},
+		{"all=1 without key", "?all=1", "", http.StatusUnauthorized},
+		{"all=true with key", "?all=true", "operator-key", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			api := e.Group("/api/v1", When(response.UnpaginatedRequested, AdminKey("operator-key")))
+			api.GET("/users", func(c *echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/users"+tt.query, nil)
+			if tt.header != "" {
+				req.Header.Set("X-Admin-Key", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"slices"
+	"sync/atomic"
+	"time"
+
+	appErrors "helpdesk/internal/utils/errors"
+	"helpdesk/internal/utils/response"
+
+	"github.com/labstack/echo/v5"
+)
+
+type ConcurrencyLimiter struct {
+	slots        chan struct{}
+	queueTimeout time.Duration
+	exemptPaths  []string
+	inFlight     atomic.Int64
+}
+
+// NewConcurrencyLimiter caps in-flight requests at limit. Requests over the
+// limit wait up to queueTimeout for a slot before getting a 503. A limit of
+// 0 or less disables the limiter.
+func NewConcurrencyLimiter(limit int, queueTimeout time.Duration, exemptPaths ...string) *ConcurrencyLimiter {
+	l := &ConcurrencyLimiter{
+		queueTimeout: queueTimeout,
+		exemptPaths:  exemptPaths,
+	}
+	if limit > 0 {
+		l.slots = make(chan struct{}, limit)
+	}
+	return l
+}
+
+func (l *ConcurrencyLimiter) InFlight() int64 {
+	return l.inFlight.Load()
+}
+
+func (l *ConcurrencyLimiter) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			if slices.Contains(l.exemptPaths, c.Request().URL.Path) {
+				return next(c)
+			}
+
+			if l.slots != nil {
+				if !l.acquire(c) {
+					return response.Error(c, appErrors.ServiceUnavailable("Server is busy, please retry"))
+				}
+				defer func() { <-l.slots }()
+			}
+
+			l.inFlight.Add(1)
+			defer l.inFlight.Add(-1)
+
+			return next(c)
+		}
+	}
+}
+
+func (l *ConcurrencyLimiter) acquire(c *echo.Context) bool {
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	if l.queueTimeout <= 0 {
+		return false
+	}
+
+	timer := time.NewTimer(l.queueTimeout)
+	defer timer.Stop()
+
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-c.Request().Context().Done():
+		return false
+	}
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"time"
+
+	"helpdesk/internal/utils/response"
+
+	"github.com/labstack/echo/v5"
+)
+
+// Deprecations adds deprecation headers to responses of the configured routes,
+// keyed by "METHOD /route" with the planned removal date as value.
+func Deprecations(routes map[string]time.Time) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			route := c.Request().Method + " " + c.Path()
+			if sunset, ok := routes[route]; ok {
+				response.SetDeprecation(c, route, sunset)
+			}
+			return next(c)
+		}
+	}
+}
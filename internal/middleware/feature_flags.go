@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"slices"
+	"strings"
+
+	appErrors "helpdesk/internal/utils/errors"
+	"helpdesk/internal/utils/response"
+
+	"github.com/labstack/echo/v5"
+)
+
+// FeatureFlags reads the comma-separated X-Feature-Flags header. Flags outside
+// response.ExperimentalFlags are rejected with a 400; known flags that are not
+// in enabled are ignored, so canary clients keep working when a rollout is
+// switched off.
+func FeatureFlags(enabled []string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			header := c.Request().Header.Get("X-Feature-Flags")
+			if header == "" {
+				return next(c)
+			}
+
+			flags := make(map[string]bool)
+			for _, flag := range strings.Split(header, ",") {
+				flag = strings.TrimSpace(flag)
+				if flag == "" {
+					continue
+				}
+				if !slices.Contains(response.ExperimentalFlags, flag) {
+					return response.Error(c, appErrors.BadRequest("Unknown feature flag: "+flag).WithDetails(map[string]interface{}{
+						"allowed": response.ExperimentalFlags,
+					}))
+				}
+				if slices.Contains(enabled, flag) {
+					flags[flag] = true
+				}
+			}
+
+			response.SetFeatureFlags(c, flags)
+			return next(c)
+		}
+	}
+}
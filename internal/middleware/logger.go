@@ -2,12 +2,25 @@ package middleware
 
 import (
 	"log/slog"
+	"net/http"
+	"sync/atomic"
 	"time"
 
+	"helpdesk/internal/utils/response"
+
 	"github.com/labstack/echo/v5"
 )
 
-func Logger(logger *slog.Logger) echo.MiddlewareFunc {
+type LoggerConfig struct {
+	LatencyBudgets map[string]time.Duration
+	// SampleRate logs 1 in N successful GET requests; errors and mutations
+	// are always logged. Values below 2 log everything.
+	SampleRate int
+}
+
+func Logger(logger *slog.Logger, config LoggerConfig) echo.MiddlewareFunc {
+	var counter atomic.Uint64
+
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c *echo.Context) error {
 			start := time.Now()
@@ -21,16 +34,41 @@ func Logger(logger *slog.Logger) echo.MiddlewareFunc {
 				status = res.Status
 			}
 
-			logger.Info("request",
-				"method", req.Method,
-				"uri", req.URL.Path,
-				"status", status,
-				"latency", time.Since(start).String(),
-				"ip", c.RealIP(),
-				"user_agent", req.UserAgent(),
-			)
+			latency := time.Since(start)
+
+			if shouldLog(req.Method, status, err, config.SampleRate, &counter) {
+				attrs := []any{
+					"request_id", response.GetRequestID(c),
+					"method", req.Method,
+					"uri", req.URL.Path,
+					"status", status,
+					"latency", latency.String(),
+					"ip", c.RealIP(),
+					"user_agent", req.UserAgent(),
+				}
+				if originalID := response.GetOriginalRequestID(c); originalID != "" {
+					attrs = append(attrs, "original_request_id", originalID)
+				}
+				logger.Info("request", attrs...)
+			}
+
+			route := req.Method + " " + c.Path()
+			if budget, ok := config.LatencyBudgets[route]; ok && latency > budget {
+				logger.Warn("latency budget exceeded",
+					"route", route,
+					"latency", latency.String(),
+					"budget", budget.String(),
+				)
+			}
 
 			return err
 		}
 	}
 }
+
+func shouldLog(method string, status int, err error, sampleRate int, counter *atomic.Uint64) bool {
+	if sampleRate < 2 || err != nil || method != http.MethodGet || status < 200 || status >= 300 {
+		return true
+	}
+	return counter.Add(1)%uint64(sampleRate) == 1
+}
@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"helpdesk/internal/utils/response"
+
+	"github.com/labstack/echo/v5"
+)
+
+// PaginationStyle applies the deployment's PAGINATION_STYLE to every list
+// response: "body", "header", or "both".
+func PaginationStyle(style string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			response.SetPaginationStyle(c, style)
+			return next(c)
+		}
+	}
+}
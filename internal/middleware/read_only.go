@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"sync/atomic"
+
+	appErrors "helpdesk/internal/utils/errors"
+	"helpdesk/internal/utils/response"
+
+	"github.com/labstack/echo/v5"
+)
+
+type ReadOnlyMode struct {
+	enabled     atomic.Bool
+	exemptPaths []string
+}
+
+// NewReadOnlyMode rejects mutating requests with a 503 while enabled. The
+// flag can be flipped at runtime; exempt paths always pass through.
+func NewReadOnlyMode(enabled bool, exemptPaths ...string) *ReadOnlyMode {
+	m := &ReadOnlyMode{exemptPaths: exemptPaths}
+	m.enabled.Store(enabled)
+	return m
+}
+
+func (m *ReadOnlyMode) Enabled() bool {
+	return m.enabled.Load()
+}
+
+func (m *ReadOnlyMode) SetEnabled(enabled bool) {
+	m.enabled.Store(enabled)
+}
+
+func (m *ReadOnlyMode) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			if !m.Enabled() || isReadMethod(c.Request().Method) || slices.Contains(m.exemptPaths, c.Request().URL.Path) {
+				return next(c)
+			}
+
+			return response.Error(c, appErrors.ServiceUnavailable("API is in read-only mode for maintenance; only read requests are accepted"))
+		}
+	}
+}
+
+func isReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
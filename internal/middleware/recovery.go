@@ -2,18 +2,17 @@ package middleware
 
 import (
 	"fmt"
+	appErrors "helpdesk/internal/utils/errors"
 	"helpdesk/internal/utils/response"
 	"log/slog"
-	"net/http"
 	"runtime/debug"
-	"time"
 
 	"github.com/labstack/echo/v5"
 )
 
 func Recovery(logger *slog.Logger) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
-		return func(c *echo.Context) error {
+		return func(c *echo.Context) (returnErr error) {
 			defer func() {
 				if r := recover(); r != nil {
 					err, ok := r.(error)
@@ -21,22 +20,19 @@ func Recovery(logger *slog.Logger) echo.MiddlewareFunc {
 						err = fmt.Errorf("%v", r)
 					}
 
+					requestID := response.GetRequestID(c)
 					stack := debug.Stack()
 					logger.Error("panic recovered",
 						"error", err,
 						"stack", string(stack),
 						"uri", c.Request().URL.Path,
 						"method", c.Request().Method,
+						"request_id", requestID,
 					)
 
-					c.JSON(http.StatusInternalServerError, response.Response{
-						Error: &response.ErrorInfo{
-							Message: "Internal server error",
-						},
-						Meta: &response.Meta{
-							Timestamp: time.Now().UTC().Format(time.RFC3339),
-						},
-					})
+					returnErr = response.Error(c, appErrors.Internal("Internal server error").WithDetails(map[string]interface{}{
+						"requestId": requestID,
+					}))
 				}
 			}()
 
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	appErrors "helpdesk/internal/utils/errors"
+	"helpdesk/internal/utils/response"
+
+	"github.com/labstack/echo/v5"
+)
+
+func TestRecoveryReturnsErrorEnvelope(t *testing.T) {
+	logger := slog.New(slog.NewJSONHandler(io.Discard, nil))
+
+	e := echo.New()
+	e.Use(RequestID(RequestIDModeLenient))
+	e.Use(Recovery(logger))
+	e.GET("/panic", func(c *echo.Context) error {
+		panic("secret panic detail")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	req.Header.Set("X-Request-ID", "test-request-123")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+
+	body := rec.Body.String()
+	if strings.Contains(body, "secret panic detail") || strings.Contains(body, "goroutine") {
+		t.Errorf("response leaks panic value or stack trace: %s", body)
+	}
+
+	var resp response.Response
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid JSON response: %v", err)
+	}
+
+	if resp.Error == nil {
+		t.Fatalf("response has no error object: %s", body)
+	}
+	if resp.Error.Code != appErrors.CODE_INTERNAL_ERROR {
+		t.Errorf("error.code = %q, want %q", resp.Error.Code, appErrors.CODE_INTERNAL_ERROR)
+	}
+	if got := resp.Error.Details["requestId"]; got != "test-request-123" {
+		t.Errorf("error.details.requestId = %v, want %q", got, "test-request-123")
+	}
+	if resp.Meta == nil {
+		t.Error("response has no meta")
+	}
+}
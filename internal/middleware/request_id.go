@@ -1,22 +1,55 @@
 package middleware
 
 import (
+	"regexp"
+
 	"helpdesk/internal/utils/response"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v5"
 )
 
-func RequestID(next echo.HandlerFunc) echo.HandlerFunc {
-	return func(c *echo.Context) error {
-		requestID := c.Request().Header.Get("X-Request-ID")
-		if requestID == "" {
-			requestID = uuid.New().String()
-		}
+const (
+	RequestIDModeOff     = "off"
+	RequestIDModeLenient = "lenient"
+	RequestIDModeStrict  = "strict"
+)
+
+var lenientRequestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{8,128}$`)
+
+// RequestID trusts client-supplied IDs according to mode: "off" accepts any
+// value, "lenient" accepts short opaque tokens, and "strict" requires a UUID.
+// Rejected values are replaced and echoed back as X-Original-Request-ID.
+func RequestID(mode string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			requestID := c.Request().Header.Get("X-Request-ID")
+
+			if requestID != "" && !validRequestID(requestID, mode) {
+				response.SetOriginalRequestID(c, requestID)
+				c.Response().Header().Set("X-Original-Request-ID", requestID)
+				requestID = ""
+			}
 
-		response.SetRequestID(c, requestID)
-		c.Response().Header().Set("X-Request-ID", requestID)
+			if requestID == "" {
+				requestID = uuid.New().String()
+			}
+
+			response.SetRequestID(c, requestID)
+			c.Response().Header().Set("X-Request-ID", requestID)
+
+			return next(c)
+		}
+	}
+}
 
-		return next(c)
+func validRequestID(requestID, mode string) bool {
+	switch mode {
+	case RequestIDModeOff:
+		return true
+	case RequestIDModeStrict:
+		return uuid.Validate(requestID) == nil
+	default:
+		return lenientRequestIDPattern.MatchString(requestID)
 	}
 }
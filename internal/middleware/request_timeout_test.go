@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v5"
+)
+
+func TestRequestTimeoutSetsDeadline(t *testing.T) {
+	tests := []struct {
+		name         string
+		timeout      time.Duration
+		wantDeadline bool
+	}{
+		{"enabled", time.Second, true},
+		{"disabled", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.Use(RequestTimeout(tt.timeout))
+
+			var deadline time.Time
+			var hasDeadline bool
+			e.GET("/", func(c *echo.Context) error {
+				deadline, hasDeadline = c.Request().Context().Deadline()
+				return c.NoContent(http.StatusOK)
+			})
+
+			e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if hasDeadline != tt.wantDeadline {
+				t.Fatalf("has deadline = %v, want %v", hasDeadline, tt.wantDeadline)
+			}
+			if hasDeadline && time.Until(deadline) > tt.timeout {
+				t.Errorf("deadline in %v, want at most %v", time.Until(deadline), tt.timeout)
+			}
+		})
+	}
+}
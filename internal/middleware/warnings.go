@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"helpdesk/internal/utils/response"
+
+	"github.com/labstack/echo/v5"
+)
+
+// Warnings prepares the request context so services can attach non-fatal
+// warnings to the success response.
+func Warnings() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			c.SetRequest(c.Request().WithContext(response.WithWarnings(c.Request().Context())))
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import "github.com/labstack/echo/v5"
+
+// When applies mw only to requests for which condition returns true, e.g. to
+// require the admin key for DELETE ?mode=hard but not for a soft delete.
+func When(condition func(c *echo.Context) bool, mw echo.MiddlewareFunc) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		guarded := mw(next)
+		return func(c *echo.Context) error {
+			if condition(c) {
+				return guarded(c)
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Stats struct {
+	Hits    uint64 `json:"hits"`
+	Misses  uint64 `json:"misses"`
+	Entries int    `json:"entries"`
+	Enabled bool   `json:"enabled"`
+}
+
+type entry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// TTLCache is a process-local cache. A nil *TTLCache is a valid disabled
+// cache, so callers don't need to check whether caching is configured.
+type TTLCache[V any] struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[string]entry[V]
+	hits    atomic.Uint64
+	misses  atomic.Uint64
+}
+
+func New[V any](ttl time.Duration) *TTLCache[V] {
+	if ttl <= 0 {
+		return nil
+	}
+
+	return &TTLCache[V]{
+		ttl:     ttl,
+		entries: make(map[string]entry[V]),
+	}
+}
+
+func (c *TTLCache[V]) Get(key string) (V, bool) {
+	var zero V
+	if c == nil {
+		return zero, false
+	}
+
+	c.mu.RLock()
+	e, ok := c.entries[key]
+	c.mu.RUnlock()
+
+	if !ok || time.Now().After(e.expiresAt) {
+		c.misses.Add(1)
+		return zero, false
+	}
+
+	c.hits.Add(1)
+	return e.value, true
+}
+
+func (c *TTLCache[V]) Set(key string, value V) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for k, e := range c.entries {
+		if now.After(e.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+
+	c.entries[key] = entry[V]{value: value, expiresAt: now.Add(c.ttl)}
+}
+
+func (c *TTLCache[V]) Clear() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	c.entries = make(map[string]entry[V])
+	c.mu.Unlock()
+}
+
+func (c *TTLCache[V]) Stats() Stats {
+	if c == nil {
+		return Stats{}
+	}
+
+	c.mu.RLock()
+	entries := len(c.entries)
+	c.mu.RUnlock()
+
+	return Stats{
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+		Entries: entries,
+		Enabled: true,
+	}
+}
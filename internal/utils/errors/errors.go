@@ -1,9 +1,13 @@
 package errors
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
+
+	"github.com/lib/pq"
 )
 
 const (
@@ -12,14 +16,30 @@ const (
 	CODE_VALIDATION_ERROR = "VALIDATION_ERROR"
 	CODE_INTERNAL_ERROR   = "INTERNAL_SERVER_ERROR"
 	CODE_BAD_REQUEST      = "BAD_REQUEST"
+	CODE_CONFLICT         = "CONFLICT"
+	CODE_UNAUTHORIZED     = "UNAUTHORIZED"
+	CODE_FORBIDDEN        = "FORBIDDEN"
+
+	CODE_CLIENT_CLOSED_REQUEST = "CLIENT_CLOSED_REQUEST"
+	CODE_SERVICE_UNAVAILABLE   = "SERVICE_UNAVAILABLE"
 )
 
+const StatusClientClosedRequest = 499
+
+const pqQueryCanceled = "57014"
+
 var (
 	ErrNotFound      = errors.New("resource not found")
 	ErrAlreadyExists = errors.New("resource already exists")
 	ErrValidation    = errors.New("validation error")
 	ErrInternal      = errors.New("internal server error")
 	ErrBadRequest    = errors.New("bad request")
+	ErrConflict      = errors.New("conflict")
+	ErrUnauthorized  = errors.New("unauthorized")
+	ErrForbidden     = errors.New("forbidden")
+
+	ErrClientClosedRequest = errors.New("client closed request")
+	ErrServiceUnavailable  = errors.New("service unavailable")
 )
 
 type AppError struct {
@@ -73,6 +93,12 @@ func AlreadyExists(resource string) *AppError {
 	}
 }
 
+func AlreadyExistsWithID(resource string, existingID int) *AppError {
+	return AlreadyExists(resource).WithDetails(map[string]interface{}{
+		"existingId": existingID,
+	})
+}
+
 func Validation(message string) *AppError {
 	return &AppError{
 		Err:        ErrValidation,
@@ -99,3 +125,83 @@ func BadRequest(message string) *AppError {
 		StatusCode: http.StatusBadRequest,
 	}
 }
+
+func Conflict(message string) *AppError {
+	return &AppError{
+		Err:        ErrConflict,
+		Code:       CODE_CONFLICT,
+		Message:    message,
+		StatusCode: http.StatusConflict,
+	}
+}
+
+func Unauthorized(message string) *AppError {
+	return &AppError{
+		Err:        ErrUnauthorized,
+		Code:       CODE_UNAUTHORIZED,
+		Message:    message,
+		StatusCode: http.StatusUnauthorized,
+	}
+}
+
+func Forbidden(message string) *AppError {
+	return &AppError{
+		Err:        ErrForbidden,
+		Code:       CODE_FORBIDDEN,
+		Message:    message,
+		StatusCode: http.StatusForbidden,
+	}
+}
+
+func ClientClosedRequest() *AppError {
+	return &AppError{
+		Err:        ErrClientClosedRequest,
+		Code:       CODE_CLIENT_CLOSED_REQUEST,
+		Message:    "Client closed request",
+		StatusCode: StatusClientClosedRequest,
+	}
+}
+
+func ServiceUnavailable(message string) *AppError {
+	return &AppError{
+		Err:        ErrServiceUnavailable,
+		Code:       CODE_SERVICE_UNAVAILABLE,
+		Message:    message,
+		StatusCode: http.StatusServiceUnavailable,
+	}
+}
+
+// FromContext maps a cancelled or timed-out operation to 499 or 503 and
+// returns nil for any other error. lib/pq reports a query interrupted by its
+// context as error 57014 (query_canceled) instead of the context error, so
+// ctx, the request context, tells a client disconnect from a timeout.
+func FromContext(ctx context.Context, err error) *AppError {
+	if !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) && !isQueryCanceled(err) {
+		return nil
+	}
+
+	if errors.Is(err, context.Canceled) || (ctx != nil && errors.Is(ctx.Err(), context.Canceled)) {
+		return ClientClosedRequest()
+	}
+	return ServiceUnavailable("Request timed out")
+}
+
+func isQueryCanceled(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pqQueryCanceled
+}
+
+// FromRepository turns a repository failure into an AppError. Cancellations
+// and timeouts are expected under load, so they are logged at debug level
+// instead of being reported as internal errors.
+func FromRepository(ctx context.Context, logger *slog.Logger, err error, logMessage, message string, args ...any) *AppError {
+	args = append([]any{"error", err}, args...)
+
+	if ctxErr := FromContext(ctx, err); ctxErr != nil {
+		logger.Debug(logMessage, args...)
+		return ctxErr
+	}
+
+	logger.Error(logMessage, args...)
+	return Internal(message)
+}
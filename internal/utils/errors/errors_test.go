@@ -0,0 +1,64 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestFromContext(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	queryCanceled := fmt.Errorf("failed to get users: %w", &pq.Error{Code: pqQueryCanceled, Message: "canceling statement due to user request"})
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		err        error
+		wantStatus int
+	}{
+		{"context canceled", context.Background(), context.Canceled, StatusClientClosedRequest},
+		{"deadline exceeded", context.Background(), context.DeadlineExceeded, http.StatusServiceUnavailable},
+		{"query canceled by client disconnect", canceled, queryCanceled, StatusClientClosedRequest},
+		{"query canceled by timeout", context.Background(), queryCanceled, http.StatusServiceUnavailable},
+		{"other database error", canceled, &pq.Error{Code: "23505"}, 0},
+		{"plain error", context.Background(), fmt.Errorf("boom"), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FromContext(tt.ctx, tt.err)
+			if tt.wantStatus == 0 {
+				if got != nil {
+					t.Errorf("FromContext() = %d, want nil", got.StatusCode)
+				}
+				return
+			}
+			if got == nil || got.StatusCode != tt.wantStatus {
+				t.Errorf("FromContext() = %v, want status %d", got, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestFromRepositoryLogsQueryCancelAtDebug(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	err := FromRepository(ctx, logger, &pq.Error{Code: pqQueryCanceled}, "failed to get users", "Failed to retrieve users")
+
+	if err.StatusCode != StatusClientClosedRequest {
+		t.Errorf("status = %d, want %d", err.StatusCode, StatusClientClosedRequest)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("logged at info level or above: %s", logs.String())
+	}
+}
@@ -0,0 +1,26 @@
+package nullable
+
+import "encoding/json"
+
+// Field tells an omitted JSON field (Set false) apart from an explicit null
+// (Set true, Valid false) and a value (Set and Valid true).
+type Field[T any] struct {
+	Set   bool
+	Valid bool
+	Value T
+}
+
+func (f *Field[T]) UnmarshalJSON(data []byte) error {
+	f.Set = true
+	if string(data) == "null" {
+		f.Valid = false
+		return nil
+	}
+
+	f.Valid = true
+	return json.Unmarshal(data, &f.Value)
+}
+
+func (f Field[T]) IsNull() bool {
+	return f.Set && !f.Valid
+}
@@ -0,0 +1,46 @@
+package nullable
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type patch struct {
+	Phone Field[string] `json:"phone"`
+}
+
+func TestFieldUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		want   Field[string]
+		isNull bool
+	}{
+		{"omitted", `{}`, Field[string]{}, false},
+		{"null", `{"phone": null}`, Field[string]{Set: true}, true},
+		{"empty string", `{"phone": ""}`, Field[string]{Set: true, Valid: true}, false},
+		{"value", `{"phone": "08123456789"}`, Field[string]{Set: true, Valid: true, Value: "08123456789"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p patch
+			if err := json.Unmarshal([]byte(tt.body), &p); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if p.Phone != tt.want {
+				t.Errorf("Field = %+v, want %+v", p.Phone, tt.want)
+			}
+			if p.Phone.IsNull() != tt.isNull {
+				t.Errorf("IsNull() = %v, want %v", p.Phone.IsNull(), tt.isNull)
+			}
+		})
+	}
+}
+
+func TestFieldUnmarshalJSONRejectsWrongType(t *testing.T) {
+	var p patch
+	if err := json.Unmarshal([]byte(`{"phone": 123}`), &p); err == nil {
+		t.Error("Unmarshal() accepted a number for a string field")
+	}
+}
@@ -0,0 +1,31 @@
+package query
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DistinctValues returns the non-null distinct values of a column. The
+// column must come from a repository allowlist, never from user input.
+func DistinctValues(ctx context.Context, db *sqlx.DB, table, column string) ([]any, error) {
+	distinctQuery := fmt.Sprintf(`SELECT DISTINCT %[2]s FROM %[1]s WHERE %[2]s IS NOT NULL ORDER BY %[2]s`, table, column)
+
+	rows, err := db.QueryContext(ctx, distinctQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := []any{}
+	for rows.Next() {
+		var value any
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+
+	return values, rows.Err()
+}
@@ -0,0 +1,54 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+
+	appErrors "helpdesk/internal/utils/errors"
+)
+
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+func ParseSort(value string, allowed ...string) (*Sort, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	sort := &Sort{Field: value}
+	if strings.HasPrefix(value, "-") {
+		sort.Field = strings.TrimPrefix(value, "-")
+		sort.Desc = true
+	}
+
+	for _, field := range allowed {
+		if sort.Field == field {
+			return sort, nil
+		}
+	}
+
+	return nil, appErrors.BadRequest(fmt.Sprintf("Sort must be one of: %s (prefix with - for descending)", strings.Join(allowed, ", ")))
+}
+
+// OrderBy always appends the tiebreaker column so rows with equal sort values
+// keep a stable order across pages.
+func OrderBy(sort *Sort, columns map[string]string, defaultOrder, tiebreaker string) string {
+	if sort == nil {
+		return " ORDER BY " + defaultOrder
+	}
+
+	column, ok := columns[sort.Field]
+	if !ok {
+		return " ORDER BY " + defaultOrder
+	}
+
+	direction := "ASC"
+	if sort.Desc {
+		direction = "DESC"
+	}
+
+	return fmt.Sprintf(" ORDER BY %s %s, %s %s", column, direction, tiebreaker, direction)
+}
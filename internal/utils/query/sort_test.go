@@ -0,0 +1,128 @@
+package query
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+var testSortColumns = map[string]string{
+	"name":      "name",
+	"createdAt": "created_at",
+}
+
+func TestOrderByAppendsTiebreaker(t *testing.T) {
+	tests := []struct {
+		name string
+		sort *Sort
+		want string
+	}{
+		{"no sort", nil, " ORDER BY created_at DESC, id DESC"},
+		{"unknown field", &Sort{Field: "email"}, " ORDER BY created_at DESC, id DESC"},
+		{"ascending", &Sort{Field: "name"}, " ORDER BY name ASC, id ASC"},
+		{"descending", &Sort{Field: "name", Desc: true}, " ORDER BY name DESC, id DESC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := OrderBy(tt.sort, testSortColumns, "created_at DESC, id DESC", "id")
+			if got != tt.want {
+				t.Errorf("OrderBy() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    *Sort
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"name", &Sort{Field: "name"}, false},
+		{"-name", &Sort{Field: "name", Desc: true}, false},
+		{"email", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseSort(tt.value, "name", "createdAt")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSort(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("ParseSort(%q) = %+v, want nil", tt.value, got)
+				}
+				return
+			}
+			if got == nil || *got != *tt.want {
+				t.Errorf("ParseSort(%q) = %+v, want %+v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+type testRow struct {
+	id   int
+	name string
+}
+
+// fetchPage emulates a database that returns rows with equal sort keys in an
+// arbitrary order: each call starts from a differently rotated table.
+func fetchPage(rows []testRow, orderBy string, rotation, limit, offset int) []testRow {
+	table := append(append([]testRow{}, rows[rotation%len(rows):]...), rows[:rotation%len(rows)]...)
+
+	var keys [][2]string
+	for _, part := range strings.Split(strings.TrimPrefix(orderBy, " ORDER BY "), ",") {
+		fields := strings.Fields(part)
+		keys = append(keys, [2]string{fields[0], fields[1]})
+	}
+
+	slices.SortStableFunc(table, func(a, b testRow) int {
+		for _, key := range keys {
+			var c int
+			switch key[0] {
+			case "name":
+				c = strings.Compare(a.name, b.name)
+			case "id":
+				c = a.id - b.id
+			}
+			if key[1] == "DESC" {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+
+	end := min(offset+limit, len(table))
+	return table[offset:end]
+}
+
+func TestOrderByPaginatesDuplicateNames(t *testing.T) {
+	var rows []testRow
+	for i := 1; i <= 12; i++ {
+		rows = append(rows, testRow{id: i, name: []string{"alpha", "beta", "gamma"}[i%3]})
+	}
+
+	for _, desc := range []bool{false, true} {
+		orderBy := OrderBy(&Sort{Field: "name", Desc: desc}, testSortColumns, "created_at DESC, id DESC", "id")
+
+		seen := make(map[int]int)
+		for page := 0; page*5 < len(rows); page++ {
+			for _, row := range fetchPage(rows, orderBy, page*7, 5, page*5) {
+				seen[row.id]++
+			}
+		}
+
+		for _, row := range rows {
+			if seen[row.id] != 1 {
+				t.Errorf("desc=%v: row %d appeared %d times across pages", desc, row.id, seen[row.id])
+			}
+		}
+	}
+}
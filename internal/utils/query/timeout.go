@@ -0,0 +1,30 @@
+package query
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	OpSearch   = "search"
+	OpList     = "list"
+	OpStats    = "stats"
+	OpDistinct = "distinct"
+)
+
+// Timeouts are per-operation query timeouts keyed by the Op constants.
+// Operations without an entry keep the request context's deadline, which
+// middleware.RequestTimeout sets from REQUEST_TIMEOUT.
+type Timeouts map[string]time.Duration
+
+// WithTimeout bounds ctx by the timeout configured for op. A query that runs
+// past it fails with lib/pq error 57014 (or context.DeadlineExceeded when it
+// has not reached the server yet), which appErrors.FromRepository maps to
+// 503.
+func (t Timeouts) WithTimeout(ctx context.Context, op string) (context.Context, context.CancelFunc) {
+	timeout, ok := t[op]
+	if !ok || timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
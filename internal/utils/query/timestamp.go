@@ -0,0 +1,13 @@
+package query
+
+import "time"
+
+// Timestamp converts an optional time to a query argument for a TIMESTAMP
+// column. The columns store UTC without a zone, so t is converted first; nil
+// becomes NULL.
+func Timestamp(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
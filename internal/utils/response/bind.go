@@ -0,0 +1,40 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"helpdesk/internal/utils/errors"
+	"helpdesk/internal/utils/validator"
+	"io"
+
+	"github.com/labstack/echo/v5"
+)
+
+// BindPatch rejects bodies that carry read-only fields before binding, so
+// clients resending a whole object get told instead of silently ignored.
+func BindPatch(c *echo.Context, dst any, immutable []string) error {
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return errors.BadRequest("Invalid request body")
+	}
+	c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+	if len(bytes.TrimSpace(body)) > 0 {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(body, &fields); err != nil {
+			return errors.BadRequest("Invalid request body")
+		}
+
+		v := validator.New()
+		for _, field := range immutable {
+			if _, ok := fields[field]; ok {
+				v.AddErrorWithCode(field, validator.CODE_IMMUTABLE, "Field cannot be changed")
+			}
+		}
+		if !v.Valid() {
+			return v.ToAppError()
+		}
+	}
+
+	return c.Bind(dst)
+}
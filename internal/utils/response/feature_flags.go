@@ -0,0 +1,25 @@
+package response
+
+import "github.com/labstack/echo/v5"
+
+const FlagLinkPagination = "linkPagination"
+
+// ExperimentalFlags lists every flag a client can request with the
+// X-Feature-Flags header. Only response-shape experiments belong here, never
+// anything that relaxes validation, limits, or access checks.
+var ExperimentalFlags = []string{FlagLinkPagination}
+
+func SetFeatureFlags(c *echo.Context, flags map[string]bool) {
+	if c != nil {
+		c.Set("featureFlags", flags)
+	}
+}
+
+// HasFeatureFlag reports whether the request enabled an experimental flag.
+func HasFeatureFlag(c *echo.Context, flag string) bool {
+	if c == nil {
+		return false
+	}
+	flags, _ := c.Get("featureFlags").(map[string]bool)
+	return flags[flag]
+}
@@ -0,0 +1,112 @@
+package response
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+)
+
+const (
+	PaginationStyleBody   = "body"
+	PaginationStyleHeader = "header"
+	PaginationStyleBoth   = "both"
+)
+
+type linkPaginated interface {
+	linkPagination() (PaginationResponse, int)
+	hidePagination()
+}
+
+// SetPaginationStyle chooses where list pagination goes for this request:
+// the JSON body (default), RFC 5988 Link headers, or both.
+func SetPaginationStyle(c *echo.Context, style string) {
+	if c != nil {
+		c.Set("paginationStyle", style)
+	}
+}
+
+func getPaginationStyle(c *echo.Context) string {
+	if c == nil {
+		return PaginationStyleBody
+	}
+	switch style, _ := c.Get("paginationStyle").(string); style {
+	case PaginationStyleHeader, PaginationStyleBoth:
+		return style
+	default:
+		return PaginationStyleBody
+	}
+}
+
+func (l *ListResponse[T]) linkPagination() (PaginationResponse, int) {
+	if l.Pagination == nil {
+		return PaginationResponse{}, len(l.Items)
+	}
+	return *l.Pagination, len(l.Items)
+}
+
+func (l *ListResponse[T]) hidePagination() {
+	l.Pagination = nil
+}
+
+func applyPaginationStyle(c *echo.Context, data interface{}) {
+	style := getPaginationStyle(c)
+	if style == PaginationStyleBody && HasFeatureFlag(c, FlagLinkPagination) {
+		style = PaginationStyleBoth
+	}
+	if style == PaginationStyleBody {
+		return
+	}
+
+	list, ok := data.(linkPaginated)
+	if !ok {
+		return
+	}
+
+	pagination, itemCount := list.linkPagination()
+	if pagination.Limit > 0 {
+		setLinkHeader(c, pagination, itemCount)
+	}
+
+	if style == PaginationStyleHeader {
+		list.hidePagination()
+	}
+}
+
+// setLinkHeader emits first/prev/next/last links built from the current
+// request URL. Without totals, last is omitted and next is guessed from a
+// full page.
+func setLinkHeader(c *echo.Context, p PaginationResponse, itemCount int) {
+	header := c.Response().Header()
+
+	links := []string{pageLink(c, 1, "first")}
+	if p.Page > 1 {
+		links = append(links, pageLink(c, p.Page-1, "prev"))
+	}
+
+	if p.TotalPages != nil {
+		if p.Page < *p.TotalPages {
+			links = append(links, pageLink(c, p.Page+1, "next"))
+		}
+		links = append(links, pageLink(c, max(*p.TotalPages, 1), "last"))
+	} else if itemCount >= p.Limit {
+		links = append(links, pageLink(c, p.Page+1, "next"))
+	}
+
+	header.Set("Link", strings.Join(links, ", "))
+
+	if p.TotalItems != nil {
+		header.Set("X-Total-Count", strconv.Itoa(*p.TotalItems))
+	}
+}
+
+func pageLink(c *echo.Context, page int, rel string) string {
+	req := c.Request()
+
+	params := req.URL.Query()
+	params.Set("page", strconv.Itoa(page))
+
+	url := fmt.Sprintf("%s://%s%s?%s", c.Scheme(), req.Host, req.URL.Path, params.Encode())
+	return fmt.Sprintf(`<%s>; rel="%s"`, url, rel)
+}
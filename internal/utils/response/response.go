@@ -1,8 +1,13 @@
 package response
 
 import (
+	"context"
+	"fmt"
 	"helpdesk/internal/utils/errors"
+	"helpdesk/internal/utils/validator"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,10 +20,11 @@ type Meta struct {
 }
 
 type Response struct {
-	Message string      `json:"message,omitempty"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   *ErrorInfo  `json:"error,omitempty"`
-	Meta    *Meta       `json:"meta"`
+	Message  string      `json:"message,omitempty"`
+	Data     interface{} `json:"data,omitempty"`
+	Warnings []Warning   `json:"warnings,omitempty"`
+	Error    *ErrorInfo  `json:"error,omitempty"`
+	Meta     *Meta       `json:"meta"`
 }
 
 type ErrorInfo struct {
@@ -28,38 +34,145 @@ type ErrorInfo struct {
 }
 
 type PaginationResponse struct {
-	Page       int `json:"page"`
-	Limit      int `json:"limit"`
-	TotalItems int `json:"totalItems"`
-	TotalPages int `json:"totalPages"`
+	Page       int  `json:"page"`
+	Limit      int  `json:"limit"`
+	TotalItems *int `json:"totalItems,omitempty"`
+	TotalPages *int `json:"totalPages,omitempty"`
 }
 
 type ListResponse[T any] struct {
-	Items      []T                `json:"items"`
-	Pagination PaginationResponse `json:"pagination"`
+	Items      []T                 `json:"items"`
+	Pagination *PaginationResponse `json:"pagination,omitempty"`
 }
 
+// Clone copies the items and pagination so a cached list can be handed out
+// without callers sharing its backing arrays.
+func (l *ListResponse[T]) Clone() *ListResponse[T] {
+	clone := &ListResponse[T]{Items: slices.Clone(l.Items)}
+	if l.Pagination != nil {
+		pagination := *l.Pagination
+		if l.Pagination.TotalItems != nil {
+			totalItems := *l.Pagination.TotalItems
+			pagination.TotalItems = &totalItems
+		}
+		if l.Pagination.TotalPages != nil {
+			totalPages := *l.Pagination.TotalPages
+			pagination.TotalPages = &totalPages
+		}
+		clone.Pagination = &pagination
+	}
+	return clone
+}
+
+type IDsRequest struct {
+	IDs []int `json:"ids"`
+}
+
+// DateRange is a half-open range [From, To) for date filters.
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+type ExistenceResult struct {
+	Exists   bool `json:"exists"`
+	IsActive bool `json:"isActive"`
+}
+
+type DistinctQuery struct {
+	Field string `query:"field"`
+}
+
+type DeleteQuery struct {
+	Mode string `query:"mode"`
+}
+
+type DistinctValues struct {
+	Field  string `json:"field"`
+	Values []any  `json:"values"`
+}
+
+const (
+	DefaultPage    = 1
+	DefaultLimit   = 10
+	MaxLimit       = 100
+	MaxUnpaginated = 100000
+	MaxBatchIDs    = 100
+	DistinctMaxAge = 60
+)
+
 const (
-	DefaultPage  = 1
-	DefaultLimit = 10
-	MaxLimit     = 100
+	DeleteModeHard = "hard"
+	DeleteModeSoft = "soft"
 )
 
+var ValidDeleteModes = []string{DeleteModeHard, DeleteModeSoft}
+
+const (
+	DateToday     = "today"
+	DateYesterday = "yesterday"
+	DateThisWeek  = "thisWeek"
+)
+
+// ListOptions are the configured limits list queries are normalized against.
+type ListOptions struct {
+	// AllowUnpaginated enables the all=true escape hatch for admin bulk reads.
+	AllowUnpaginated bool
+	// MaxPage rejects pages beyond it to avoid deep OFFSET scans; 0 disables
+	// the cap.
+	MaxPage int
+	// Location is the timezone date-only filters are interpreted in, so
+	// "today" means today for users in that zone. nil means UTC.
+	Location *time.Location
+}
+
+// Now returns the current time in the date filter location.
+func (o ListOptions) Now() time.Time {
+	if o.Location == nil {
+		return time.Now().UTC()
+	}
+	return time.Now().In(o.Location)
+}
+
 type PaginationQuery struct {
-	Page  int `query:"page"`
-	Limit int `query:"limit"`
+	Page      int   `query:"page"`
+	Limit     int   `query:"limit"`
+	All       bool  `query:"all"`
+	WithTotal *bool `query:"withTotal"`
 }
 
-func (p *PaginationQuery) NormalizePagination() (page int, limit int, offset int) {
-	page = p.Page
+type Pagination struct {
+	Page        int
+	Limit       int
+	Offset      int
+	WithTotal   bool
+	Unpaginated bool
+}
+
+func (p *PaginationQuery) NormalizePagination(opts ListOptions) (Pagination, error) {
+	withTotal := p.WithTotal == nil || *p.WithTotal
+
+	if p.All {
+		if !opts.AllowUnpaginated {
+			return Pagination{}, errors.BadRequest("Unpaginated listing is disabled")
+		}
+		return Pagination{Page: DefaultPage, Limit: MaxUnpaginated, WithTotal: true, Unpaginated: true}, nil
+	}
+
+	page := p.Page
 	if page == 0 {
 		page = DefaultPage
 	}
 	if page < 1 {
 		page = DefaultPage
 	}
+	if opts.MaxPage > 0 && page > opts.MaxPage {
+		return Pagination{}, errors.BadRequest("Page is too deep, narrow the results with filters instead").WithDetails(map[string]interface{}{
+			"maxPage": opts.MaxPage,
+		})
+	}
 
-	limit = p.Limit
+	limit := p.Limit
 	if limit == 0 {
 		limit = DefaultLimit
 	}
@@ -70,30 +183,167 @@ func (p *PaginationQuery) NormalizePagination() (page int, limit int, offset int
 		limit = MaxLimit
 	}
 
-	offset = (page - 1) * limit
-	return
+	return Pagination{
+		Page:      page,
+		Limit:     limit,
+		Offset:    (page - 1) * limit,
+		WithTotal: withTotal,
+	}, nil
+}
+
+func (r *IDsRequest) Validate() error {
+	v := validator.New()
+
+	validator.ValidateIDs(v, "ids", r.IDs, MaxBatchIDs)
+
+	if !v.Valid() {
+		return v.ToAppError()
+	}
+
+	return nil
+}
+
+func (q *DistinctQuery) Validate(allowed []string) error {
+	v := validator.New()
+
+	validator.ValidateEnum(v, "field", q.Field, allowed, true)
+
+	if !v.Valid() {
+		return v.ToAppError()
+	}
+
+	return nil
+}
+
+// Normalize returns the requested delete mode, falling back to defaultMode;
+// a default other than "soft" means "hard".
+func (q *DeleteQuery) Normalize(defaultMode string) (string, error) {
+	mode := strings.TrimSpace(q.Mode)
+	if mode == "" {
+		if defaultMode == DeleteModeSoft {
+			return DeleteModeSoft, nil
+		}
+		return DeleteModeHard, nil
+	}
+
+	v := validator.New()
+	validator.ValidateEnum(v, "mode", mode, ValidDeleteModes, true)
+	if !v.Valid() {
+		return "", v.ToAppError()
+	}
+
+	return mode, nil
+}
+
+// UnpaginatedRequested reports whether a list request asks for all=true, so
+// the admin key can be required for it.
+func UnpaginatedRequested(c *echo.Context) bool {
+	all, err := strconv.ParseBool(c.QueryParam("all"))
+	return err == nil && all
+}
+
+// HardDeleteRequested reports whether a DELETE request resolves to a hard
+// delete, either through ?mode=hard or through defaultMode. Invalid modes
+// report false and are rejected by DeleteQuery.Normalize later.
+func HardDeleteRequested(defaultMode string) func(c *echo.Context) bool {
+	return func(c *echo.Context) bool {
+		q := DeleteQuery{Mode: c.QueryParam("mode")}
+		mode, err := q.Normalize(defaultMode)
+		return err == nil && mode == DeleteModeHard
+	}
+}
+
+// ParseDateFilter accepts YYYY-MM-DD or one of the keywords today, yesterday,
+// and thisWeek (Monday to today). Day boundaries are taken in now's location.
+func ParseDateFilter(value string, now time.Time) (*DateRange, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch value {
+	case DateToday:
+		return &DateRange{From: today, To: today.AddDate(0, 0, 1)}, nil
+	case DateYesterday:
+		return &DateRange{From: today.AddDate(0, 0, -1), To: today}, nil
+	case DateThisWeek:
+		daysSinceMonday := (int(today.Weekday()) + 6) % 7
+		return &DateRange{From: today.AddDate(0, 0, -daysSinceMonday), To: today.AddDate(0, 0, 1)}, nil
+	}
+
+	parsed, err := time.ParseInLocation("2006-01-02", value, now.Location())
+	if err != nil {
+		return nil, errors.BadRequest("Date must use YYYY-MM-DD format or one of: today, yesterday, thisWeek")
+	}
+
+	return &DateRange{From: parsed, To: parsed.AddDate(0, 0, 1)}, nil
 }
 
-func ParseDate(dateStr string) (*time.Time, error) {
-	if strings.TrimSpace(dateStr) == "" {
+// ParseMonthFilter accepts YYYY-MM and returns that calendar month in the
+// location of now. Months after the current one are rejected.
+func ParseMonthFilter(field, value string, now time.Time) (*DateRange, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
 		return nil, nil
 	}
 
-	parsed, err := time.Parse("2006-01-02", strings.TrimSpace(dateStr))
+	parsed, err := time.ParseInLocation("2006-01", value, now.Location())
 	if err != nil {
-		return nil, errors.BadRequest("Date must use YYYY-MM-DD format")
+		return nil, errors.BadRequest(field + " must use YYYY-MM format")
+	}
+
+	currentMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	if parsed.After(currentMonth) {
+		return nil, errors.BadRequest(field + " cannot be in the future")
 	}
 
-	return &parsed, nil
+	return &DateRange{From: parsed, To: parsed.AddDate(0, 1, 0)}, nil
 }
 
+// CalculateTotalPages returns ceil(totalItems / limit). It is 0 only when
+// there are no items, at least 1 otherwise, and a non-positive limit counts
+// as a single page rather than dividing by zero.
 func CalculateTotalPages(totalItems, limit int) int {
-	if totalItems == 0 {
+	if totalItems <= 0 {
 		return 0
 	}
+	if limit <= 0 {
+		return 1
+	}
 	return (totalItems + limit - 1) / limit
 }
 
+// WarnIfTruncated adds a RESULT_TRUNCATED warning when an all=true listing
+// matched more rows than it returned. Unpaginated listings always count
+// totals for this.
+func WarnIfTruncated(ctx context.Context, p Pagination, totalItems int) {
+	if p.Unpaginated && totalItems > p.Limit {
+		AddWarning(ctx, WarningResultTruncated, fmt.Sprintf("Only the first %d of %d matching rows were returned; narrow the filters to export the rest", p.Limit, totalItems))
+	}
+}
+
+// NewListResponse omits the totals when the client opted out of counting
+// with withTotal=false.
+func NewListResponse[T any](items []T, p Pagination, totalItems int) *ListResponse[T] {
+	pagination := PaginationResponse{
+		Page:  p.Page,
+		Limit: p.Limit,
+	}
+
+	if p.WithTotal {
+		totalPages := CalculateTotalPages(totalItems, p.Limit)
+		pagination.TotalItems = &totalItems
+		pagination.TotalPages = &totalPages
+	}
+
+	return &ListResponse[T]{
+		Items:      items,
+		Pagination: &pagination,
+	}
+}
+
 func GetRequestID(c *echo.Context) string {
 	if c == nil {
 		return uuid.New().String()
@@ -110,6 +360,20 @@ func SetRequestID(c *echo.Context, requestID string) {
 	}
 }
 
+func GetOriginalRequestID(c *echo.Context) string {
+	if c == nil {
+		return ""
+	}
+	id, _ := c.Get("originalRequestId").(string)
+	return id
+}
+
+func SetOriginalRequestID(c *echo.Context, requestID string) {
+	if c != nil {
+		c.Set("originalRequestId", requestID)
+	}
+}
+
 func buildMeta(c *echo.Context) *Meta {
 	return &Meta{
 		Timestamp: time.Now().UTC().Format(time.RFC3339),
@@ -117,10 +381,13 @@ func buildMeta(c *echo.Context) *Meta {
 }
 
 func Success(c *echo.Context, statusCode int, message string, data interface{}) error {
+	applyPaginationStyle(c, data)
+
 	return c.JSON(statusCode, Response{
-		Message: message,
-		Data:    data,
-		Meta:    buildMeta(c),
+		Message:  message,
+		Data:     data,
+		Warnings: getWarnings(c),
+		Meta:     buildMeta(c),
 	})
 }
 
@@ -132,6 +399,32 @@ func OK(c *echo.Context, message string, data interface{}) error {
 	return Success(c, http.StatusOK, message, data)
 }
 
+// SetLocation points the Location header at a resource, keeping the API
+// prefix of the current request, e.g. POST /api/v1/users -> /api/v1/users/123.
+func SetLocation(c *echo.Context, resource string, id int) {
+	path := c.Request().URL.Path
+	prefix := ""
+	if idx := strings.Index(path, "/"+resource); idx >= 0 {
+		prefix = path[:idx]
+	}
+	c.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("%s/%s/%d", prefix, resource, id))
+}
+
+// SetDeprecation announces that route will be removed after sunset with the
+// Deprecation, Sunset (RFC 8594), and Warning headers. It only adds headers,
+// so caching and compression of the response are unchanged.
+func SetDeprecation(c *echo.Context, route string, sunset time.Time) {
+	header := c.Response().Header()
+	header.Set("Deprecation", "true")
+	header.Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+	header.Set("Warning", fmt.Sprintf(`299 - "%s is deprecated and will be removed after %s"`, route, sunset.Format("2006-01-02")))
+}
+
+// SetCacheControl marks a response as cacheable by the client for maxAge seconds.
+func SetCacheControl(c *echo.Context, maxAge int) {
+	c.Response().Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d", maxAge))
+}
+
 func NoContent(c *echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
@@ -0,0 +1,96 @@
+package response
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCalculateTotalPages(t *testing.T) {
+	tests := []struct {
+		name       string
+		totalItems int
+		limit      int
+		want       int
+	}{
+		{"zero items", 0, 10, 0},
+		{"negative items", -5, 10, 0},
+		{"zero limit", 25, 0, 1},
+		{"negative limit", 25, -1, 1},
+		{"fewer items than limit", 3, 10, 1},
+		{"exact multiple", 30, 10, 3},
+		{"single exact page", 10, 10, 1},
+		{"remainder", 31, 10, 4},
+		{"limit of one", 7, 1, 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CalculateTotalPages(tt.totalItems, tt.limit); got != tt.want {
+				t.Errorf("CalculateTotalPages(%d, %d) = %d, want %d", tt.totalItems, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewListResponsePagination(t *testing.T) {
+	list := NewListResponse([]int{1, 2, 3}, Pagination{Page: 2, Limit: 3, WithTotal: true}, 7)
+
+	p := list.Pagination
+	if p.Page != 2 || p.Limit != 3 {
+		t.Errorf("page, limit = %d, %d, want 2, 3", p.Page, p.Limit)
+	}
+	if p.TotalItems == nil || *p.TotalItems != 7 {
+		t.Errorf("totalItems = %v, want 7", p.TotalItems)
+	}
+	if p.TotalPages == nil || *p.TotalPages != 3 {
+		t.Errorf("totalPages = %v, want 3", p.TotalPages)
+	}
+
+	withoutTotal := NewListResponse([]int{}, Pagination{Page: 1, Limit: 10}, 7)
+	if withoutTotal.Pagination.TotalItems != nil || withoutTotal.Pagination.TotalPages != nil {
+		t.Error("totals are set when withTotal is false")
+	}
+}
+
+func TestNormalizePaginationAll(t *testing.T) {
+	withTotal := false
+	q := PaginationQuery{All: true, WithTotal: &withTotal}
+
+	if _, err := q.NormalizePagination(ListOptions{}); err == nil {
+		t.Error("NormalizePagination() with all=true succeeded while unpaginated listing is disabled")
+	}
+
+	got, err := q.NormalizePagination(ListOptions{AllowUnpaginated: true})
+	if err != nil {
+		t.Fatalf("NormalizePagination() error = %v", err)
+	}
+	want := Pagination{Page: DefaultPage, Limit: MaxUnpaginated, WithTotal: true, Unpaginated: true}
+	if got != want {
+		t.Errorf("NormalizePagination() = %+v, want %+v", got, want)
+	}
+}
+
+func TestWarnIfTruncated(t *testing.T) {
+	tests := []struct {
+		name       string
+		pagination Pagination
+		totalItems int
+		want       bool
+	}{
+		{"unpaginated under the cap", Pagination{Limit: MaxUnpaginated, Unpaginated: true}, MaxUnpaginated, false},
+		{"unpaginated over the cap", Pagination{Limit: MaxUnpaginated, Unpaginated: true}, MaxUnpaginated + 1, true},
+		{"paginated with more pages", Pagination{Limit: DefaultLimit}, 50, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := WithWarnings(context.Background())
+			WarnIfTruncated(ctx, tt.pagination, tt.totalItems)
+
+			warnings := ctx.Value(warningsKey{}).(*warningCollector).warnings
+			if got := len(warnings) == 1 && warnings[0].Code == WarningResultTruncated; got != tt.want {
+				t.Errorf("warnings = %+v, want truncated warning %v", warnings, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package response
+
+import (
+	"context"
+	"sync"
+
+	"github.com/labstack/echo/v5"
+)
+
+const (
+	WarningInactiveDivision = "INACTIVE_DIVISION"
+	WarningResultTruncated  = "RESULT_TRUNCATED"
+)
+
+// Warning is a non-fatal notice attached to a successful response.
+type Warning struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type warningsKey struct{}
+
+type warningCollector struct {
+	mu       sync.Mutex
+	warnings []Warning
+}
+
+// WithWarnings returns a context that services can add warnings to with
+// AddWarning; Success includes them in the response envelope.
+func WithWarnings(ctx context.Context) context.Context {
+	return context.WithValue(ctx, warningsKey{}, &warningCollector{})
+}
+
+// AddWarning records a warning for the current request. It is a no-op when
+// ctx was not prepared with WithWarnings.
+func AddWarning(ctx context.Context, code, message string) {
+	collector, ok := ctx.Value(warningsKey{}).(*warningCollector)
+	if !ok {
+		return
+	}
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	collector.warnings = append(collector.warnings, Warning{Code: code, Message: message})
+}
+
+func getWarnings(c *echo.Context) []Warning {
+	collector, ok := c.Request().Context().Value(warningsKey{}).(*warningCollector)
+	if !ok {
+		return nil
+	}
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	return collector.warnings
+}
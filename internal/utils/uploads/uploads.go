@@ -8,7 +8,11 @@ package uploads
 
 import (
 	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
 	"io"
+	"mime"
 	"mime/multipart"
 	"os"
 	"path/filepath"
@@ -16,6 +20,8 @@ import (
 	"time"
 
 	appErrors "helpdesk/internal/utils/errors"
+
+	_ "golang.org/x/image/webp"
 )
 
 const (
@@ -26,6 +32,13 @@ const (
 	FileDir        = "uploads/file"
 )
 
+// ImageLimits is the largest accepted image size in pixels; 0 leaves a
+// dimension uncapped.
+type ImageLimits struct {
+	MaxWidth  int
+	MaxHeight int
+}
+
 var AllowedImageExtensions = map[string]bool{
 	".jpg":  true,
 	".jpeg": true,
@@ -54,7 +67,7 @@ func EnsureUploadDirs() error {
 	return nil
 }
 
-func ValidateImageFile(fileHeader *multipart.FileHeader) error {
+func ValidateImageFile(fileHeader *multipart.FileHeader, limits ImageLimits) error {
 	if fileHeader.Size > MaxImageSize {
 		return appErrors.BadRequest("Image size exceeds maximum limit of 5MB")
 	}
@@ -64,6 +77,25 @@ func ValidateImageFile(fileHeader *multipart.FileHeader) error {
 		return appErrors.BadRequest("Invalid image type. Only jpg, jpeg, png, and webp are allowed")
 	}
 
+	return validateImageDimensions(fileHeader, limits)
+}
+
+func validateImageDimensions(fileHeader *multipart.FileHeader, limits ImageLimits) error {
+	src, err := fileHeader.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open uploaded file: %w", err)
+	}
+	defer src.Close()
+
+	config, _, err := image.DecodeConfig(src)
+	if err != nil || config.Width <= 0 || config.Height <= 0 {
+		return appErrors.BadRequest("Image is corrupt or has invalid dimensions")
+	}
+
+	if (limits.MaxWidth > 0 && config.Width > limits.MaxWidth) || (limits.MaxHeight > 0 && config.Height > limits.MaxHeight) {
+		return appErrors.BadRequest(fmt.Sprintf("Image dimensions exceed maximum of %dx%d pixels", limits.MaxWidth, limits.MaxHeight))
+	}
+
 	return nil
 }
 
@@ -80,24 +112,24 @@ func ValidateDocumentFile(fileHeader *multipart.FileHeader) error {
 	return nil
 }
 
-func SaveImageFile(fileHeader *multipart.FileHeader) (string, error) {
-	if err := ValidateImageFile(fileHeader); err != nil {
+func SaveImageFile(fileHeader *multipart.FileHeader, limits ImageLimits) (string, error) {
+	if err := ValidateImageFile(fileHeader, limits); err != nil {
 		return "", err
 	}
 
 	return saveFile(fileHeader, ImageAvatarDir)
 }
 
-func SaveAvatarImage(fileHeader *multipart.FileHeader) (string, error) {
-	if err := ValidateImageFile(fileHeader); err != nil {
+func SaveAvatarImage(fileHeader *multipart.FileHeader, limits ImageLimits) (string, error) {
+	if err := ValidateImageFile(fileHeader, limits); err != nil {
 		return "", err
 	}
 
 	return saveFile(fileHeader, ImageAvatarDir)
 }
 
-func SaveTicketImage(fileHeader *multipart.FileHeader) (string, error) {
-	if err := ValidateImageFile(fileHeader); err != nil {
+func SaveTicketImage(fileHeader *multipart.FileHeader, limits ImageLimits) (string, error) {
+	if err := ValidateImageFile(fileHeader, limits); err != nil {
 		return "", err
 	}
 
@@ -140,6 +172,37 @@ func saveFile(fileHeader *multipart.FileHeader, uploadDir string) (string, error
 	return "/" + filepath.ToSlash(filePath), nil
 }
 
+func OpenFile(filePath string) (*os.File, os.FileInfo, error) {
+	cleanPath := filepath.Clean(strings.TrimPrefix(filePath, "/"))
+	if !strings.HasPrefix(cleanPath, "uploads"+string(filepath.Separator)) {
+		return nil, nil, appErrors.NotFound("File")
+	}
+
+	file, err := os.Open(cleanPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, appErrors.NotFound("File")
+		}
+		return nil, nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	return file, info, nil
+}
+
+func ContentType(filePath string) string {
+	contentType := mime.TypeByExtension(strings.ToLower(filepath.Ext(filePath)))
+	if contentType == "" {
+		return "application/octet-stream"
+	}
+	return contentType
+}
+
 func DeleteFile(filePath string) error {
 	if filePath == "" {
 		return nil
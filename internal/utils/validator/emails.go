@@ -0,0 +1,74 @@
+package validator
+
+import (
+	"bufio"
+	"embed"
+	"strings"
+)
+
+//go:embed disposable_email_domains.txt
+var disposableDomainsFS embed.FS
+
+var embeddedDisposableDomains = loadDisposableDomains()
+
+// DisposableDomains is a set of disposable email provider domains.
+type DisposableDomains map[string]bool
+
+// NewDisposableDomains returns the embedded provider list plus extra.
+func NewDisposableDomains(extra []string) DisposableDomains {
+	domains := make(DisposableDomains, len(embeddedDisposableDomains)+len(extra))
+	for domain := range embeddedDisposableDomains {
+		domains[domain] = true
+	}
+	for _, domain := range extra {
+		if domain = strings.ToLower(strings.TrimSpace(domain)); domain != "" {
+			domains[domain] = true
+		}
+	}
+	return domains
+}
+
+// Contains reports whether the email's domain, or any parent domain, is in
+// the set.
+func (d DisposableDomains) Contains(email string) bool {
+	_, domain, ok := strings.Cut(strings.ToLower(strings.TrimSpace(email)), "@")
+	if !ok {
+		return false
+	}
+
+	for domain != "" {
+		if d[domain] {
+			return true
+		}
+		_, domain, _ = strings.Cut(domain, ".")
+	}
+
+	return false
+}
+
+// ValidateNotDisposableEmail adds a field error when email belongs to one of
+// domains. A nil set accepts every provider.
+func ValidateNotDisposableEmail(v *Validator, field, email string, domains DisposableDomains) {
+	if domains.Contains(email) {
+		v.AddError(field, field+" uses a disposable email provider")
+	}
+}
+
+func loadDisposableDomains() DisposableDomains {
+	domains := make(DisposableDomains)
+
+	file, err := disposableDomainsFS.Open("disposable_email_domains.txt")
+	if err != nil {
+		return domains
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := strings.ToLower(strings.TrimSpace(scanner.Text())); line != "" {
+			domains[line] = true
+		}
+	}
+
+	return domains
+}
@@ -0,0 +1,49 @@
+package validator
+
+import (
+	"bufio"
+	"embed"
+	"strings"
+)
+
+//go:embed common_passwords.txt
+var commonPasswordsFS embed.FS
+
+var (
+	commonPasswords   = loadCommonPasswords()
+	minPasswordLength = 6
+	maxPasswordLength = 255
+)
+
+func IsCommonPassword(password string) bool {
+	return commonPasswords[strings.ToLower(strings.TrimSpace(password))]
+}
+
+// ValidatePassword checks the length and, with rejectCommon, rejects passwords
+// found in the embedded common-password list.
+func ValidatePassword(v *Validator, field, password string, rejectCommon bool) {
+	ValidateString(v, field, password, true, minPasswordLength, maxPasswordLength)
+
+	if rejectCommon && IsCommonPassword(password) {
+		v.AddError(field, field+" is too common, choose a less predictable password")
+	}
+}
+
+func loadCommonPasswords() map[string]bool {
+	passwords := make(map[string]bool)
+
+	file, err := commonPasswordsFS.Open("common_passwords.txt")
+	if err != nil {
+		return passwords
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := strings.ToLower(strings.TrimSpace(scanner.Text())); line != "" {
+			passwords[line] = true
+		}
+	}
+
+	return passwords
+}
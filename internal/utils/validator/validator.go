@@ -4,17 +4,27 @@ import (
 	"fmt"
 	"helpdesk/internal/utils/errors"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 	"unicode/utf8"
 )
 
+const (
+	CODE_REQUIRED     = "REQUIRED"
+	CODE_INVALID_ENUM = "INVALID_ENUM"
+	CODE_IMMUTABLE    = "IMMUTABLE"
+)
+
 type Validator struct {
 	Errors map[string]string
+	Codes  map[string]string
 }
 
 func New() *Validator {
 	return &Validator{
 		Errors: make(map[string]string),
+		Codes:  make(map[string]string),
 	}
 }
 
@@ -28,6 +38,13 @@ func (v *Validator) AddError(field, message string) {
 	}
 }
 
+func (v *Validator) AddErrorWithCode(field, code, message string) {
+	if _, exists := v.Errors[field]; !exists {
+		v.Errors[field] = message
+		v.Codes[field] = code
+	}
+}
+
 func (v *Validator) Check(ok bool, field, message string) {
 	if !ok {
 		v.AddError(field, message)
@@ -44,6 +61,14 @@ func (v *Validator) ToAppError() *errors.AppError {
 		details[field] = msg
 	}
 
+	if len(v.Codes) > 0 {
+		codes := make(map[string]string, len(v.Codes))
+		for field, code := range v.Codes {
+			codes[field] = code
+		}
+		details["fieldCodes"] = codes
+	}
+
 	return errors.Validation("Validation failed").WithDetails(details)
 }
 
@@ -70,6 +95,14 @@ func ValidateEmail(value string) bool {
 	return regex.MatchString(value)
 }
 
+func ValidateUsername(v *Validator, field, value string) {
+	ValidateString(v, field, value, true, 3, 30)
+
+	if value != "" && !regexp.MustCompile(`^[a-zA-Z0-9._-]+$`).MatchString(value) {
+		v.AddError(field, fmt.Sprintf("%s may only contain letters, digits, '.', '_' and '-'", field))
+	}
+}
+
 func ValidateString(v *Validator, field, value string, required bool, minLen, maxLen int) {
 	if required {
 		v.Check(Required(value), field, fmt.Sprintf("%s is required", field))
@@ -84,3 +117,50 @@ func ValidateString(v *Validator, field, value string, required bool, minLen, ma
 		}
 	}
 }
+
+func ValidateEnum(v *Validator, field, value string, allowed []string, required bool) {
+	if value == "" {
+		if required {
+			v.AddErrorWithCode(field, CODE_REQUIRED, fmt.Sprintf("%s is required", field))
+		}
+		return
+	}
+
+	if !slices.Contains(allowed, value) {
+		v.AddErrorWithCode(field, CODE_INVALID_ENUM, fmt.Sprintf("%s must be one of: %s", field, strings.Join(allowed, ", ")))
+	}
+}
+
+// ValidateBackdate requires the createdAt override of an import and rejects
+// timestamps after now.
+func ValidateBackdate(v *Validator, field string, createdAt *time.Time, now time.Time) {
+	if createdAt == nil {
+		v.AddErrorWithCode(field, CODE_REQUIRED, fmt.Sprintf("%s is required", field))
+		return
+	}
+	v.Check(!createdAt.After(now), field, fmt.Sprintf("%s must not be in the future", field))
+}
+
+// ValidateFilterID rejects negative IDs; 0 means the filter is not applied.
+func ValidateFilterID(v *Validator, field string, id int) {
+	v.Check(id >= 0, field, fmt.Sprintf("%s must not be negative", field))
+}
+
+func ValidateIDs(v *Validator, field string, ids []int, max int) {
+	if len(ids) == 0 {
+		v.AddError(field, fmt.Sprintf("%s must contain at least one ID", field))
+		return
+	}
+
+	if len(ids) > max {
+		v.AddError(field, fmt.Sprintf("%s must not contain more than %d IDs", field, max))
+		return
+	}
+
+	for _, id := range ids {
+		if id <= 0 {
+			v.AddError(field, fmt.Sprintf("%s must only contain positive integers", field))
+			return
+		}
+	}
+}
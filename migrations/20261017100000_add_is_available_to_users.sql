@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN is_available BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN is_available;
@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE user_images (
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    slot VARCHAR(20) NOT NULL,
+    image_url TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, slot)
+);
+
+-- +goose Down
+DROP TABLE user_images;
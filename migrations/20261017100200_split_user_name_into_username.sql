@@ -0,0 +1,38 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN username VARCHAR(30), ADD COLUMN display_name VARCHAR(50);
+
+UPDATE users SET
+    display_name = name,
+    username = LEFT(TRIM(BOTH '.' FROM regexp_replace(LOWER(name), '[^a-z0-9._-]+', '.', 'g')), 30);
+
+UPDATE users SET username = 'user-' || id WHERE LENGTH(username) < 3;
+
+-- Later users sharing a username get their ID appended, so the oldest keeps it.
+WITH duplicates AS (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY LOWER(username) ORDER BY id) AS position
+    FROM users
+)
+UPDATE users u
+SET username = LEFT(u.username, 29 - LENGTH(u.id::text)) || '-' || u.id
+FROM duplicates d
+WHERE u.id = d.id AND d.position > 1;
+
+ALTER TABLE users ALTER COLUMN username SET NOT NULL, ALTER COLUMN display_name SET NOT NULL;
+
+CREATE UNIQUE INDEX idx_users_username_lower_unique ON users (LOWER(username));
+CREATE INDEX idx_users_display_name ON users(display_name);
+
+DROP INDEX idx_users_name;
+ALTER TABLE users DROP COLUMN name;
+
+-- +goose Down
+ALTER TABLE users ADD COLUMN name VARCHAR(50);
+
+UPDATE users SET name = display_name;
+
+ALTER TABLE users ALTER COLUMN name SET NOT NULL;
+CREATE INDEX idx_users_name ON users(name);
+
+DROP INDEX idx_users_display_name;
+DROP INDEX idx_users_username_lower_unique;
+ALTER TABLE users DROP COLUMN username, DROP COLUMN display_name;
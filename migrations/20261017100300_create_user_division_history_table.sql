@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE user_division_history (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    from_division_id INT DEFAULT NULL REFERENCES divisions(id) ON DELETE SET NULL,
+    to_division_id INT DEFAULT NULL REFERENCES divisions(id) ON DELETE SET NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_user_division_history_user ON user_division_history(user_id, changed_at DESC);
+
+-- +goose Down
+DROP TABLE user_division_history;
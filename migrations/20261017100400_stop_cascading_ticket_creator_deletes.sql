@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE tickets DROP CONSTRAINT tickets_created_by_fkey;
+ALTER TABLE tickets ADD CONSTRAINT tickets_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id);
+
+-- +goose Down
+ALTER TABLE tickets DROP CONSTRAINT tickets_created_by_fkey;
+ALTER TABLE tickets ADD CONSTRAINT tickets_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE;
@@ -0,0 +1,21 @@
+-- +goose Up
+ALTER TABLE ticket_attachments ALTER COLUMN uploaded_by DROP NOT NULL;
+ALTER TABLE ticket_attachments DROP CONSTRAINT ticket_attachments_uploaded_by_fkey;
+ALTER TABLE ticket_attachments ADD CONSTRAINT ticket_attachments_uploaded_by_fkey FOREIGN KEY (uploaded_by) REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE ticket_resolutions ALTER COLUMN resolved_by DROP NOT NULL;
+ALTER TABLE ticket_resolutions DROP CONSTRAINT ticket_resolutions_resolved_by_fkey;
+ALTER TABLE ticket_resolutions ADD CONSTRAINT ticket_resolutions_resolved_by_fkey FOREIGN KEY (resolved_by) REFERENCES users(id) ON DELETE SET NULL;
+
+-- +goose Down
+-- Rows whose user was deleted under SET NULL are the ones the old cascade
+-- would have removed; drop them so NOT NULL can be restored.
+DELETE FROM ticket_attachments WHERE uploaded_by IS NULL;
+ALTER TABLE ticket_attachments DROP CONSTRAINT ticket_attachments_uploaded_by_fkey;
+ALTER TABLE ticket_attachments ADD CONSTRAINT ticket_attachments_uploaded_by_fkey FOREIGN KEY (uploaded_by) REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE ticket_attachments ALTER COLUMN uploaded_by SET NOT NULL;
+
+DELETE FROM ticket_resolutions WHERE resolved_by IS NULL;
+ALTER TABLE ticket_resolutions DROP CONSTRAINT ticket_resolutions_resolved_by_fkey;
+ALTER TABLE ticket_resolutions ADD CONSTRAINT ticket_resolutions_resolved_by_fkey FOREIGN KEY (resolved_by) REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE ticket_resolutions ALTER COLUMN resolved_by SET NOT NULL;